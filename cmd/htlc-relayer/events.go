@@ -0,0 +1,102 @@
+// cmd/htlc-relayer/events.go
+package main
+
+import (
+    "context"
+    "fmt"
+
+    abci "github.com/tendermint/tendermint/abci/types"
+    rpchttp "github.com/tendermint/tendermint/rpc/client/http"
+    tmtypes "github.com/tendermint/tendermint/types"
+
+    "github.com/your_repo/x/htlc"
+)
+
+// statusQuery selects txs that changed the status of an HTLC
+var statusQuery = fmt.Sprintf("tm.event='Tx' AND %s.%s EXISTS", htlc.EventTypeStatusChanged, htlc.AttributeKeyID)
+
+// EventSource streams the ABCI events of committed txs
+type EventSource interface {
+    Subscribe(ctx context.Context) (<-chan []abci.Event, error)
+}
+
+// StatusChange is one htlc_status_changed event
+type StatusChange struct {
+    ID     string
+    Status string
+}
+
+// statusChanges extracts the htlc status changes from the events of a tx
+func statusChanges(events []abci.Event) []StatusChange {
+    var changes []StatusChange
+    for _, event := range events {
+        if event.Type != htlc.EventTypeStatusChanged {
+            continue
+        }
+        var change StatusChange
+        for _, attr := range event.Attributes {
+            switch string(attr.Key) {
+            case htlc.AttributeKeyID:
+                change.ID = string(attr.Value)
+            case htlc.AttributeKeyStatus:
+                change.Status = string(attr.Value)
+            }
+        }
+        if change.ID != "" {
+            changes = append(changes, change)
+        }
+    }
+    return changes
+}
+
+// CometEventSource subscribes to the node's websocket
+type CometEventSource struct {
+    client *rpchttp.HTTP
+}
+
+func NewCometEventSource(rpcAddr string) (*CometEventSource, error) {
+    client, err := rpchttp.New(rpcAddr, "/websocket")
+    if err != nil {
+        return nil, err
+    }
+    if err := client.Start(); err != nil {
+        return nil, err
+    }
+    return &CometEventSource{client: client}, nil
+}
+
+func (s *CometEventSource) Subscribe(ctx context.Context) (<-chan []abci.Event, error) {
+    results, err := s.client.Subscribe(ctx, "htlc-relayer", statusQuery)
+    if err != nil {
+        return nil, err
+    }
+
+    out := make(chan []abci.Event)
+    go func() {
+        defer close(out)
+        for {
+            select {
+            case <-ctx.Done():
+                return
+            case result, ok := <-results:
+                if !ok {
+                    return
+                }
+                data, ok := result.Data.(tmtypes.EventDataTx)
+                if !ok {
+                    continue
+                }
+                select {
+                case out <- data.Result.Events:
+                case <-ctx.Done():
+                    return
+                }
+            }
+        }
+    }()
+    return out, nil
+}
+
+func (s *CometEventSource) Close() error {
+    return s.client.Stop()
+}
@@ -0,0 +1,274 @@
+// cmd/htlc-relayer/evm.go
+package main
+
+import (
+    "bytes"
+    "context"
+    "errors"
+    "fmt"
+    "math/big"
+    "strings"
+
+    "github.com/ethereum/go-ethereum"
+    "github.com/ethereum/go-ethereum/accounts/abi"
+    "github.com/ethereum/go-ethereum/accounts/abi/bind"
+    "github.com/ethereum/go-ethereum/common"
+    "github.com/ethereum/go-ethereum/common/hexutil"
+    "github.com/ethereum/go-ethereum/core/types"
+    "github.com/ethereum/go-ethereum/crypto"
+    "github.com/ethereum/go-ethereum/rpc"
+
+    sdk "github.com/cosmos/cosmos-sdk/types"
+
+    "github.com/your_repo/x/htlc"
+)
+
+// immutablesTuple is IBaseEscrow.Immutables. Address and Timelocks are
+// user-defined uint256 types in Solidity, so they are uint256 in the ABI.
+const immutablesTuple = `{"name":"%s","type":"tuple","components":[
+    {"name":"orderHash","type":"bytes32"},
+    {"name":"hashlock","type":"bytes32"},
+    {"name":"maker","type":"uint256"},
+    {"name":"taker","type":"uint256"},
+    {"name":"token","type":"uint256"},
+    {"name":"amount","type":"uint256"},
+    {"name":"safetyDeposit","type":"uint256"},
+    {"name":"timelocks","type":"uint256"}]}`
+
+// escrowABI holds the parts of EscrowSrc and EscrowFactory used by the relayer
+var escrowABI = mustParseABI(`[
+    {"type":"function","name":"withdraw","stateMutability":"nonpayable","outputs":[],"inputs":[
+        {"name":"secret","type":"bytes32"},` + fmt.Sprintf(immutablesTuple, "immutables") + `]},
+    {"type":"event","name":"EscrowWithdrawal","anonymous":false,"inputs":[
+        {"name":"secret","type":"bytes32","indexed":false}]},
+    {"type":"event","name":"SrcEscrowCreated","anonymous":false,"inputs":[` + fmt.Sprintf(immutablesTuple, "srcImmutables") + `,
+        {"name":"dstImmutablesComplement","type":"tuple","indexed":false,"components":[
+            {"name":"maker","type":"uint256"},
+            {"name":"amount","type":"uint256"},
+            {"name":"token","type":"uint256"},
+            {"name":"safetyDeposit","type":"uint256"},
+            {"name":"chainId","type":"uint256"}]}]},
+    {"type":"error","name":"InvalidCaller","inputs":[]},
+    {"type":"error","name":"InvalidImmutables","inputs":[]},
+    {"type":"error","name":"InvalidSecret","inputs":[]},
+    {"type":"error","name":"InvalidTime","inputs":[]}
+]`)
+
+// Withdrawal checks fail either until the escrow's withdrawal stage opens, or
+// for good
+var (
+    errWithdrawalNotOpen = errors.New("escrow withdrawal stage not open yet")
+    errCannotWithdraw    = errors.New("escrow can't be withdrawn by the relayer")
+)
+
+func mustParseABI(def string) abi.ABI {
+    parsed, err := abi.JSON(strings.NewReader(def))
+    if err != nil {
+        panic(err)
+    }
+    return parsed
+}
+
+// Immutables mirrors IBaseEscrow.Immutables, field names match the ABI tuple
+type Immutables struct {
+    OrderHash     [32]byte `json:"order_hash"`
+    Hashlock      [32]byte `json:"hashlock"`
+    Maker         *big.Int `json:"maker"`
+    Taker         *big.Int `json:"taker"`
+    Token         *big.Int `json:"token"`
+    Amount        *big.Int `json:"amount"`
+    SafetyDeposit *big.Int `json:"safety_deposit"`
+    Timelocks     *big.Int `json:"timelocks"`
+}
+
+// Source escrow stages, in the order of TimelocksLib.Stage
+const (
+    stageSrcWithdrawal uint = iota
+    stageSrcPublicWithdrawal
+    stageSrcCancellation
+)
+
+// stageStart returns the unix time stage starts at, as TimelocksLib.get
+// computes it: the deployment time in the top 32 bits of timelocks plus the
+// stage's 32 bit offset
+func (i Immutables) stageStart(stage uint) uint64 {
+    deployedAt := new(big.Int).Rsh(i.Timelocks, 224).Uint64()
+    offset := new(big.Int).Rsh(i.Timelocks, 32*stage)
+    return deployedAt + offset.And(offset, big.NewInt(0xffffffff)).Uint64()
+}
+
+// escrowImmutables converts i to the module's EscrowImmutables, to derive
+// escrow addresses like the module does
+func (i Immutables) escrowImmutables() htlc.EscrowImmutables {
+    return htlc.EscrowImmutables{
+        OrderHash:     i.OrderHash[:],
+        HashLock:      i.Hashlock[:],
+        Maker:         common.BigToAddress(i.Maker).Hex(),
+        Taker:         common.BigToAddress(i.Taker).Hex(),
+        Token:         common.BigToAddress(i.Token).Hex(),
+        Amount:        sdk.NewIntFromBigInt(i.Amount),
+        SafetyDeposit: sdk.NewIntFromBigInt(i.SafetyDeposit),
+        Timelocks:     sdk.NewIntFromBigInt(i.Timelocks),
+    }
+}
+
+// EVMBackend is what the relayer needs from an EVM node. *ethclient.Client
+// and the simulated backend client both implement it.
+type EVMBackend interface {
+    bind.ContractBackend
+    bind.DeployBackend
+    BlockNumber(ctx context.Context) (uint64, error)
+}
+
+// Escrows sends escrow withdrawals and reads factory logs. Only the factory's
+// Address and SrcImplementation are used.
+type Escrows struct {
+    backend EVMBackend
+    factory htlc.EscrowFactory
+    auth    *bind.TransactOpts
+}
+
+func NewEscrows(backend EVMBackend, factory htlc.EscrowFactory, auth *bind.TransactOpts) *Escrows {
+    return &Escrows{backend: backend, factory: factory, auth: auth}
+}
+
+// SourceAddress returns where the factory deploys the source escrow with
+// immutables
+func (e *Escrows) SourceAddress(immutables Immutables) common.Address {
+    src, _ := e.factory.EscrowAddresses(immutables.escrowImmutables())
+    return src
+}
+
+// CheckWithdraw checks that withdraw(secret, immutables) on escrow succeeds
+// now: escrow is the source escrow of immutables, the relayer key is their
+// taker, the private withdrawal stage is open, and a dry run with eth_call
+// doesn't revert. Errors wrap errWithdrawalNotOpen while the stage isn't open
+// yet and errCannotWithdraw when the withdrawal can never succeed.
+func (e *Escrows) CheckWithdraw(ctx context.Context, escrow common.Address, secret [32]byte, immutables Immutables) error {
+    if src := e.SourceAddress(immutables); src != escrow {
+        return fmt.Errorf("%w: %s is not the source escrow of the immutables, expected %s", errCannotWithdraw, escrow, src)
+    }
+    if taker := common.BigToAddress(immutables.Taker); taker != e.auth.From {
+        return fmt.Errorf("%w: escrow taker is %s, not %s", errCannotWithdraw, taker, e.auth.From)
+    }
+
+    head, err := e.backend.HeaderByNumber(ctx, nil)
+    if err != nil {
+        return err
+    }
+    if start := immutables.stageStart(stageSrcWithdrawal); head.Time < start {
+        return fmt.Errorf("%w: opens at %d", errWithdrawalNotOpen, start)
+    }
+    if stop := immutables.stageStart(stageSrcCancellation); head.Time >= stop {
+        return fmt.Errorf("%w: cancellation started at %d", errCannotWithdraw, stop)
+    }
+
+    data, err := escrowABI.Pack("withdraw", secret, immutables)
+    if err != nil {
+        return err
+    }
+    _, err = e.backend.CallContract(ctx, ethereum.CallMsg{From: e.auth.From, To: &escrow, Data: data}, nil)
+    reason, reverted := revertReason(err)
+    switch {
+    case !reverted:
+        return err
+    case reason == "InvalidTime":
+        // The next block can still be before the stage the escrow checks
+        return fmt.Errorf("%w: dry run reverted with %s", errWithdrawalNotOpen, reason)
+    default:
+        return fmt.Errorf("%w: dry run reverted with %s", errCannotWithdraw, reason)
+    }
+}
+
+// revertReason returns the name of the escrow error a call reverted with, or
+// the raw revert data for other reverts. It returns false if err isn't a revert.
+func revertReason(err error) (string, bool) {
+    var dataErr rpc.DataError
+    if !errors.As(err, &dataErr) {
+        return "", false
+    }
+    hexData, ok := dataErr.ErrorData().(string)
+    if !ok {
+        return "", false
+    }
+    data, decodeErr := hexutil.Decode(hexData)
+    if decodeErr == nil && len(data) >= 4 {
+        for name, escrowErr := range escrowABI.Errors {
+            if bytes.Equal(escrowErr.ID[:4], data[:4]) {
+                return name, true
+            }
+        }
+    }
+    return hexData, true
+}
+
+// Withdraw calls escrow.withdraw(secret, immutables) and returns the tx hash
+// without waiting for it to be mined
+func (e *Escrows) Withdraw(ctx context.Context, escrow common.Address, secret [32]byte, immutables Immutables) (common.Hash, error) {
+    contract := bind.NewBoundContract(escrow, escrowABI, e.backend, e.backend, e.backend)
+    opts := *e.auth
+    opts.Context = ctx
+    tx, err := contract.Transact(&opts, "withdraw", secret, immutables)
+    if err != nil {
+        return common.Hash{}, err
+    }
+    return tx.Hash(), nil
+}
+
+// Receipt returns the receipt of a mined tx, or nil while it is pending
+func (e *Escrows) Receipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+    receipt, err := e.backend.TransactionReceipt(ctx, hash)
+    if err == ethereum.NotFound {
+        return nil, nil
+    }
+    return receipt, err
+}
+
+// ScanFactory returns the source escrow immutables announced by the factory
+// between blocks from and to, inclusive
+func (e *Escrows) ScanFactory(ctx context.Context, from, to uint64) ([]Immutables, error) {
+    logs, err := e.backend.FilterLogs(ctx, ethereum.FilterQuery{
+        FromBlock: new(big.Int).SetUint64(from),
+        ToBlock:   new(big.Int).SetUint64(to),
+        Addresses: []common.Address{common.HexToAddress(e.factory.Address)},
+        Topics:    [][]common.Hash{{escrowABI.Events["SrcEscrowCreated"].ID}},
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    immutables := make([]Immutables, 0, len(logs))
+    for _, log := range logs {
+        decoded, err := decodeSrcEscrowCreated(log)
+        if err != nil {
+            return nil, err
+        }
+        immutables = append(immutables, decoded)
+    }
+    return immutables, nil
+}
+
+func decodeSrcEscrowCreated(log types.Log) (Immutables, error) {
+    var event struct {
+        SrcImmutables           Immutables
+        DstImmutablesComplement struct {
+            Maker         *big.Int
+            Amount        *big.Int
+            Token         *big.Int
+            SafetyDeposit *big.Int
+            ChainId       *big.Int
+        }
+    }
+    if err := escrowABI.UnpackIntoInterface(&event, "SrcEscrowCreated", log.Data); err != nil {
+        return Immutables{}, fmt.Errorf("decode SrcEscrowCreated in %s: %w", log.TxHash, err)
+    }
+    return event.SrcImmutables, nil
+}
+
+// evmHashlock is the hashlock the escrows use for secret, which differs from
+// the sha256 hashlock of the htlc module
+func evmHashlock(secret [32]byte) [32]byte {
+    var hashlock [32]byte
+    copy(hashlock[:], crypto.Keccak256(secret[:]))
+    return hashlock
+}
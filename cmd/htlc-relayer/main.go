@@ -0,0 +1,114 @@
+// cmd/htlc-relayer/main.go
+
+// Command htlc-relayer watches the htlc module for claims and withdraws the
+// counterpart EVM escrows with the revealed secrets.
+package main
+
+import (
+    "context"
+    "flag"
+    "fmt"
+    "math/big"
+    "os"
+    "os/signal"
+    "syscall"
+    "time"
+
+    "github.com/ethereum/go-ethereum/accounts/abi/bind"
+    "github.com/ethereum/go-ethereum/common"
+    "github.com/ethereum/go-ethereum/crypto"
+    "github.com/ethereum/go-ethereum/ethclient"
+    "github.com/tendermint/tendermint/libs/log"
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/credentials/insecure"
+
+    "github.com/your_repo/x/htlc"
+    htlcclient "github.com/your_repo/x/htlc/client"
+)
+
+func main() {
+    if err := run(); err != nil {
+        fmt.Fprintln(os.Stderr, err)
+        os.Exit(1)
+    }
+}
+
+func run() error {
+    var (
+        nodeRPC       = flag.String("node", "tcp://localhost:26657", "CometBFT RPC address, events are read from its websocket")
+        grpcAddr      = flag.String("grpc", "localhost:9090", "Cosmos gRPC address")
+        evmRPC        = flag.String("evm-rpc", "ws://localhost:8546", "EVM JSON-RPC endpoint")
+        evmChainID    = flag.Int64("evm-chain-id", 1, "EVM chain ID used to sign withdrawals")
+        factory       = flag.String("factory", "", "EscrowFactory address announcing source escrows")
+        srcImpl       = flag.String("escrow-src-implementation", "", "EscrowSrc implementation the factory clones, to derive escrow addresses")
+        startBlock    = flag.Uint64("evm-start-block", 0, "first EVM block to scan for escrows on a fresh database")
+        externalChain = flag.String("external-chain", "ethereum", "ExternalChain value of the HTLCs to relay")
+        dbPath        = flag.String("db", "htlc-relayer.db", "path of the relayer database")
+        interval      = flag.Duration("interval", 5*time.Second, "how often pending swaps and factory logs are processed")
+    )
+    flag.Parse()
+
+    // Keep the key out of the process arguments
+    keyHex := os.Getenv("HTLC_RELAYER_EVM_KEY")
+    if keyHex == "" {
+        return fmt.Errorf("HTLC_RELAYER_EVM_KEY must hold the hex private key used for withdrawals")
+    }
+    if !common.IsHexAddress(*factory) {
+        return fmt.Errorf("invalid factory address %q", *factory)
+    }
+    if !common.IsHexAddress(*srcImpl) {
+        return fmt.Errorf("invalid escrow src implementation address %q", *srcImpl)
+    }
+    logger := log.NewTMLogger(log.NewSyncWriter(os.Stdout)).With("module", "htlc-relayer")
+
+    store, err := OpenStore(*dbPath)
+    if err != nil {
+        return err
+    }
+    defer store.Close()
+    if cursor, err := store.EVMCursor(); err != nil {
+        return err
+    } else if cursor == 0 {
+        if err := store.SetEVMCursor(*startBlock); err != nil {
+            return err
+        }
+    }
+
+    key, err := crypto.HexToECDSA(keyHex)
+    if err != nil {
+        return err
+    }
+    auth, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(*evmChainID))
+    if err != nil {
+        return err
+    }
+    evm, err := ethclient.Dial(*evmRPC)
+    if err != nil {
+        return err
+    }
+    defer evm.Close()
+
+    conn, err := grpc.Dial(*grpcAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+    if err != nil {
+        return err
+    }
+    defer conn.Close()
+    // Queries only, the relayer never signs Cosmos txs
+    htlcs := htlcclient.NewHTLCClient(htlcclient.NewGRPCChain(conn), nil, nil, htlcclient.DefaultConfig(""))
+
+    source, err := NewCometEventSource(*nodeRPC)
+    if err != nil {
+        return err
+    }
+    defer source.Close()
+
+    ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+    defer cancel()
+
+    relayer := NewRelayer(source, htlcs, NewEscrows(evm, htlc.EscrowFactory{Chain: *externalChain, Address: *factory, SrcImplementation: *srcImpl}, auth), store, *externalChain, *interval, logger)
+    logger.Info("relayer started", "node", *nodeRPC, "evm", *evmRPC, "factory", *factory)
+    if err := relayer.Run(ctx); err != nil && ctx.Err() == nil {
+        return err
+    }
+    return nil
+}
@@ -0,0 +1,320 @@
+// cmd/htlc-relayer/relayer.go
+package main
+
+import (
+    "context"
+    "errors"
+    "time"
+
+    "github.com/ethereum/go-ethereum/common"
+    abci "github.com/tendermint/tendermint/abci/types"
+    "github.com/tendermint/tendermint/libs/log"
+
+    "github.com/your_repo/tracker"
+    "github.com/your_repo/x/htlc"
+    htlcclient "github.com/your_repo/x/htlc/client"
+)
+
+// maxScanRange bounds the EVM blocks scanned for factory logs per tick
+const maxScanRange = 5000
+
+// defaultSecretLookupTimeout bounds the wait for the secret of a claimed
+// HTLC, so a secret that was already pruned doesn't stall the event loop
+const defaultSecretLookupTimeout = 5 * time.Second
+
+// Relayer watches HTLC status changes and, once a claim reveals the secret
+// on the Cosmos side, withdraws the counterpart EVM escrow with it
+type Relayer struct {
+    source        EventSource
+    htlcs         *htlcclient.HTLCClient
+    escrows       *Escrows
+    store         *Store
+    externalChain string
+    interval      time.Duration
+    logger        log.Logger
+
+    secretLookupTimeout time.Duration
+}
+
+func NewRelayer(source EventSource, htlcs *htlcclient.HTLCClient, escrows *Escrows, store *Store, externalChain string, interval time.Duration, logger log.Logger) *Relayer {
+    return &Relayer{
+        source:        source,
+        htlcs:         htlcs,
+        escrows:       escrows,
+        store:         store,
+        externalChain: externalChain,
+        interval:      interval,
+        logger:        logger,
+
+        secretLookupTimeout: defaultSecretLookupTimeout,
+    }
+}
+
+// Run relays until ctx is done. On start, tracked swaps are reconciled with
+// the chain so claims made while the relayer was down are not missed.
+func (r *Relayer) Run(ctx context.Context) error {
+    events, err := r.source.Subscribe(ctx)
+    if err != nil {
+        return err
+    }
+    ticker := time.NewTicker(r.interval)
+    defer ticker.Stop()
+
+    changed, err := r.store.Swaps.Reconcile(ctx, r.htlcs)
+    if err != nil {
+        r.logger.Error("failed to reconcile swaps", "err", err)
+    }
+    for _, swap := range changed {
+        r.logger.Info("reconciled swap", "id", swap.ID, "htlc", swap.Dest.State)
+    }
+
+    r.tick(ctx)
+    for {
+        select {
+        case <-ctx.Done():
+            return ctx.Err()
+        case txEvents, ok := <-events:
+            if !ok {
+                return errors.New("event subscription closed")
+            }
+            r.handleEvents(ctx, txEvents)
+        case <-ticker.C:
+            r.tick(ctx)
+        }
+    }
+}
+
+func (r *Relayer) handleEvents(ctx context.Context, events []abci.Event) {
+    for _, change := range statusChanges(events) {
+        if err := r.handleStatusChange(ctx, change); err != nil {
+            r.logger.Error("failed to handle status change", "id", change.ID, "status", change.Status, "err", err)
+        }
+    }
+}
+
+func (r *Relayer) handleStatusChange(ctx context.Context, change StatusChange) error {
+    switch change.Status {
+    case htlc.StatusClaimed.String():
+        return r.onClaimed(ctx, change.ID)
+    case htlc.StatusRefunded.String(), htlc.StatusRescued.String():
+        return r.onRefunded(change.ID)
+    case htlc.StatusPartiallyFilled.String():
+        // Partial fills reveal one secret per fill and need a Merkle-aware
+        // escrow call, they are not relayed
+        return nil
+    default:
+        _, err := r.track(ctx, change.ID)
+        return err
+    }
+}
+
+// track records the swap for HTLC id if its counterpart is an escrow on the
+// relayed chain. The escrow is the source leg, the HTLC the destination leg.
+// It returns false for HTLCs the relayer ignores.
+func (r *Relayer) track(ctx context.Context, id string) (bool, error) {
+    if _, err := r.store.Swaps.Get(id); err == nil {
+        return true, nil
+    } else if !errors.Is(err, tracker.ErrNotFound) {
+        return false, err
+    }
+
+    h, err := r.htlcs.Get(ctx, id)
+    if err != nil {
+        return false, err
+    }
+    if h.ExternalChain != r.externalChain || !common.IsHexAddress(h.ExternalID) {
+        return false, nil
+    }
+    r.logger.Info("tracking swap", "id", id, "escrow", h.ExternalID)
+    return true, r.store.Swaps.Put(tracker.Swap{
+        ID: id,
+        Source: tracker.Leg{
+            Chain: r.externalChain,
+            ID:    h.ExternalID,
+            State: tracker.LegLocked,
+        },
+        Dest: tracker.Leg{
+            Chain:    tracker.CosmosChain,
+            ID:       id,
+            HashLock: h.HashLock,
+            Amount:   h.Amount.String(),
+            Deadline: h.TimeLock,
+            State:    tracker.LegLocked,
+        },
+    })
+}
+
+func (r *Relayer) onClaimed(ctx context.Context, id string) error {
+    tracked, err := r.track(ctx, id)
+    if err != nil || !tracked {
+        return err
+    }
+    swap, err := r.store.Swaps.Get(id)
+    if err != nil {
+        return err
+    }
+    if swap.Dest.State != tracker.LegLocked {
+        return nil
+    }
+
+    lookupCtx, cancel := context.WithTimeout(ctx, r.secretLookupTimeout)
+    revealed, err := r.htlcs.WaitForClaim(lookupCtx, swap.Dest.HashLock)
+    cancel()
+    if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+        // The secret was pruned before the event was handled. Record the
+        // claim, the secret has to be revealed from the other chain.
+        r.logger.Error("secret of claimed htlc not found", "id", id)
+        return r.store.Swaps.Update(id, func(swap *tracker.Swap) error {
+            swap.Dest.State = tracker.LegClaimed
+            swap.SecretMissing = true
+            return nil
+        })
+    }
+    if err != nil {
+        return err
+    }
+    if err := r.store.Swaps.RevealSecret(id, revealed.Secret); err != nil {
+        return err
+    }
+    err = r.store.Swaps.Update(id, func(swap *tracker.Swap) error {
+        swap.Dest.State = tracker.LegClaimed
+        return nil
+    })
+    if err != nil {
+        return err
+    }
+    swap, err = r.store.Swaps.Get(id)
+    if err != nil {
+        return err
+    }
+    return r.relay(ctx, swap)
+}
+
+func (r *Relayer) onRefunded(id string) error {
+    err := r.store.Swaps.Update(id, func(swap *tracker.Swap) error {
+        swap.Dest.State = tracker.LegRefunded
+        // The escrow is cancelled by its own timelocks, nothing to relay
+        swap.State = tracker.SwapCancelled
+        return nil
+    })
+    if errors.Is(err, tracker.ErrNotFound) {
+        return nil
+    }
+    return err
+}
+
+// relay sends the escrow withdrawal for a swap whose secret is known. Without
+// immutables for the secret yet, the swap is retried on a later tick.
+func (r *Relayer) relay(ctx context.Context, swap tracker.Swap) error {
+    var secret [32]byte
+    if len(swap.Secret) != len(secret) {
+        return r.fail(swap.ID, "secret is not 32 bytes")
+    }
+    copy(secret[:], swap.Secret)
+
+    immutables, found, err := r.store.GetImmutables(evmHashlock(secret))
+    if err != nil || !found {
+        return err
+    }
+    escrow := common.HexToAddress(swap.Source.ID)
+    switch err := r.escrows.CheckWithdraw(ctx, escrow, secret, immutables); {
+    case errors.Is(err, errWithdrawalNotOpen):
+        // Retried on a later tick
+        r.logger.Info("escrow withdrawal not open yet", "id", swap.ID, "escrow", swap.Source.ID, "reason", err)
+        return nil
+    case errors.Is(err, errCannotWithdraw):
+        return r.fail(swap.ID, err.Error())
+    case err != nil:
+        return err
+    }
+    txHash, err := r.escrows.Withdraw(ctx, escrow, secret, immutables)
+    if err != nil {
+        return err
+    }
+    r.logger.Info("sent escrow withdrawal", "id", swap.ID, "escrow", swap.Source.ID, "tx", txHash)
+    return r.store.Swaps.Update(swap.ID, func(swap *tracker.Swap) error {
+        swap.Source.State, swap.Source.TxHash = tracker.LegClaiming, txHash.Hex()
+        return nil
+    })
+}
+
+// confirm settles the escrow leg of a swap once its withdrawal is mined
+func (r *Relayer) confirm(ctx context.Context, swap tracker.Swap) error {
+    receipt, err := r.escrows.Receipt(ctx, common.HexToHash(swap.Source.TxHash))
+    if err != nil || receipt == nil {
+        return err
+    }
+    return r.store.Swaps.Update(swap.ID, func(swap *tracker.Swap) error {
+        if receipt.Status == 1 {
+            swap.Source.State = tracker.LegClaimed
+            swap.Settle()
+        } else {
+            // relay checks the escrow again before resending, and only fails
+            // the swap if the withdrawal can't succeed later
+            swap.Source.State, swap.Source.TxHash = tracker.LegLocked, ""
+            swap.Error = "escrow withdrawal reverted"
+        }
+        r.logger.Info("escrow withdrawal mined", "id", swap.ID, "state", swap.State, "success", receipt.Status == 1)
+        return nil
+    })
+}
+
+// fail marks a swap failed for good
+func (r *Relayer) fail(id, reason string) error {
+    return r.store.Swaps.Update(id, func(swap *tracker.Swap) error {
+        swap.State, swap.Error = tracker.SwapFailed, reason
+        return nil
+    })
+}
+
+// tick scans the factory for new escrows and advances active swaps
+func (r *Relayer) tick(ctx context.Context) {
+    if err := r.scanFactory(ctx); err != nil {
+        r.logger.Error("failed to scan escrow factory", "err", err)
+    }
+
+    swaps, err := r.store.Swaps.Active()
+    if err != nil {
+        r.logger.Error("failed to load active swaps", "err", err)
+        return
+    }
+    for _, swap := range swaps {
+        switch {
+        case swap.Source.State == tracker.LegLocked && len(swap.Secret) > 0:
+            err = r.relay(ctx, swap)
+        case swap.Source.State == tracker.LegClaiming:
+            err = r.confirm(ctx, swap)
+        default:
+            continue
+        }
+        if err != nil {
+            r.logger.Error("failed to advance swap", "id", swap.ID, "err", err)
+        }
+    }
+}
+
+func (r *Relayer) scanFactory(ctx context.Context) error {
+    from, err := r.store.EVMCursor()
+    if err != nil {
+        return err
+    }
+    head, err := r.escrows.backend.BlockNumber(ctx)
+    if err != nil || from > head {
+        return err
+    }
+    to := head
+    if to-from >= maxScanRange {
+        to = from + maxScanRange - 1
+    }
+
+    found, err := r.escrows.ScanFactory(ctx, from, to)
+    if err != nil {
+        return err
+    }
+    for _, immutables := range found {
+        if err := r.store.PutImmutables(immutables); err != nil {
+            return err
+        }
+    }
+    return r.store.SetEVMCursor(to + 1)
+}
@@ -0,0 +1,499 @@
+// cmd/htlc-relayer/relayer_test.go
+package main
+
+import (
+    "bytes"
+    "context"
+    "math/big"
+    "path/filepath"
+    "testing"
+    "time"
+
+    "github.com/ethereum/go-ethereum"
+    "github.com/ethereum/go-ethereum/accounts/abi"
+    "github.com/ethereum/go-ethereum/accounts/abi/bind"
+    "github.com/ethereum/go-ethereum/common"
+    "github.com/ethereum/go-ethereum/core/types"
+    "github.com/ethereum/go-ethereum/crypto"
+    "github.com/ethereum/go-ethereum/ethclient/simulated"
+    "github.com/stretchr/testify/require"
+    abci "github.com/tendermint/tendermint/abci/types"
+    "github.com/tendermint/tendermint/libs/log"
+
+    "github.com/cosmos/cosmos-sdk/codec"
+    codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+    cryptocodec "github.com/cosmos/cosmos-sdk/crypto/codec"
+    "github.com/cosmos/cosmos-sdk/crypto/hd"
+    "github.com/cosmos/cosmos-sdk/crypto/keyring"
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    authtx "github.com/cosmos/cosmos-sdk/x/auth/tx"
+
+    "github.com/your_repo/tracker"
+    "github.com/your_repo/x/htlc"
+    htlcclient "github.com/your_repo/x/htlc/client"
+    "github.com/your_repo/x/htlc/testutil"
+)
+
+var genesisTime = time.Unix(1_700_000_000, 0).UTC()
+
+// initCode wraps runtime in constructor code that deploys it unchanged
+func initCode(runtime []byte) []byte {
+    n := byte(len(runtime))
+    code := []byte{
+        0x60, n, 0x60, 0x0c, 0x60, 0x00, 0x39, // CODECOPY(0, 12, n)
+        0x60, n, 0x60, 0x00, 0xf3, // RETURN(0, n)
+    }
+    return append(code, runtime...)
+}
+
+// revertWith reverts with the selector of the escrow error name
+func revertWith(name string) []byte {
+    code := []byte{0x63} // PUSH4
+    code = append(code, escrowABI.Errors[name].ID[:4]...)
+    return append(code, 0x60, 0xe0, 0x1b, 0x60, 0x00, 0x52, 0x60, 0x04, 0x60, 0x00, 0xfd) // MSTORE(0, selector << 224), REVERT(0, 4)
+}
+
+// mockEscrowCode stands in for EscrowSrc.withdraw(secret, immutables). Like
+// onlyTaker and onlyAfter(SrcWithdrawal) it reverts with InvalidCaller unless
+// the caller is the taker and with InvalidTime before the withdrawal stage,
+// then emits EscrowWithdrawal with the secret.
+func mockEscrowCode() []byte {
+    // onlyTaker: the taker is the 4th immutables word
+    runtime := []byte{0x60, 0x84, 0x35, 0x33, 0x14, 0x60, 0x00, 0x57} // PUSH1 0x84, CALLDATALOAD, CALLER, EQ, PUSH1 ok, JUMPI
+    takerOK := len(runtime) - 2
+    runtime = append(runtime, revertWith("InvalidCaller")...)
+    runtime[takerOK] = byte(len(runtime))
+
+    // onlyAfter: deployedAt (top 32 bits of timelocks) + SrcWithdrawal offset
+    runtime = append(runtime,
+        0x5b,                   // JUMPDEST
+        0x61, 0x01, 0x04, 0x35, // PUSH2 0x104, CALLDATALOAD
+        0x80, 0x63, 0xff, 0xff, 0xff, 0xff, 0x16, // DUP1, PUSH4 0xffffffff, AND
+        0x90, 0x60, 0xe0, 0x1c, 0x01, // SWAP1, PUSH1 224, SHR, ADD
+        0x42, 0x10, 0x60, 0x00, 0x57, // TIMESTAMP, LT, PUSH1 early, JUMPI
+    )
+    early := len(runtime) - 2
+
+    runtime = append(runtime, 0x60, 0x20, 0x60, 0x04, 0x60, 0x00, 0x37, 0x7f) // CALLDATACOPY(0, 4, 32), PUSH32
+    runtime = append(runtime, escrowABI.Events["EscrowWithdrawal"].ID.Bytes()...)
+    runtime = append(runtime, 0x60, 0x20, 0x60, 0x00, 0xa1, 0x00) // LOG1(0, 32, topic), STOP
+
+    runtime[early] = byte(len(runtime))
+    runtime = append(runtime, 0x5b) // JUMPDEST
+    runtime = append(runtime, revertWith("InvalidTime")...)
+    return initCode(runtime)
+}
+
+// mockFactoryCode emits SrcEscrowCreated with the raw calldata as event data
+// and, like EscrowFactory, deploys an EIP-1167 proxy of implementation with
+// CREATE2, salted with the hash of the immutables
+func mockFactoryCode(implementation common.Address) []byte {
+    runtime := []byte{0x36, 0x60, 0x00, 0x60, 0x00, 0x37, 0x7f} // CALLDATACOPY(0, 0, CALLDATASIZE), PUSH32
+    runtime = append(runtime, escrowABI.Events["SrcEscrowCreated"].ID.Bytes()...)
+    runtime = append(runtime, 0x36, 0x60, 0x00, 0xa1) // LOG1(0, CALLDATASIZE, topic)
+    runtime = append(runtime,
+        0x61, 0x01, 0x00, 0x60, 0x00, 0x20, // KECCAK256(0, 256): the immutables words
+        0x60, 0x37, 0x60, 0x00, 0x61, 0x02, 0x00, 0x39, // CODECOPY(0x200, proxy, 55)
+        0x60, 0x37, 0x61, 0x02, 0x00, 0x60, 0x00, 0xf5, // CREATE2(0, 0x200, 55, salt)
+        0x50, 0x00, // POP, STOP
+    )
+    runtime[len(runtime)-15] = byte(len(runtime))
+    runtime = append(runtime, common.FromHex("0x3d602d80600a3d3981f3363d3d373d3d3d363d73")...)
+    runtime = append(runtime, implementation.Bytes()...)
+    runtime = append(runtime, common.FromHex("0x5af43d82803e903d91602b57fd5bf3")...)
+    return initCode(runtime)
+}
+
+type relayerFixture struct {
+    relayer *Relayer
+    store   *Store
+    events  <-chan []abci.Event
+
+    chain    *testutil.MockChain
+    client   *htlcclient.HTLCClient
+    receiver sdk.AccAddress
+
+    backend *simulated.Backend
+    auth    *bind.TransactOpts
+    factory common.Address
+}
+
+func setupRelayer(t *testing.T) relayerFixture {
+    // Cosmos side
+    registry := codectypes.NewInterfaceRegistry()
+    cryptocodec.RegisterInterfaces(registry)
+    htlc.RegisterInterfaces(registry)
+    cdc := codec.NewProtoCodec(registry)
+    txConfig := authtx.NewTxConfig(cdc, authtx.DefaultSignModes)
+    kr := keyring.NewInMemory(cdc)
+    var addrs []sdk.AccAddress
+    for _, name := range []string{"sender", "receiver"} {
+        record, _, err := kr.NewMnemonic(name, keyring.English, sdk.FullFundraiserPath, keyring.DefaultBIP39Passphrase, hd.Secp256k1)
+        require.NoError(t, err)
+        addr, err := record.GetAddress()
+        require.NoError(t, err)
+        addrs = append(addrs, addr)
+    }
+    chain := testutil.NewMockChain(t, txConfig.TxDecoder(), genesisTime)
+    chain.Bank.FundAccount(addrs[0], sdk.NewCoins(sdk.NewInt64Coin("atom", 1000)))
+    cfg := htlcclient.DefaultConfig("htlc-test")
+    cfg.PollInterval = time.Millisecond
+    client := htlcclient.NewHTLCClient(chain, txConfig, kr, cfg)
+
+    // EVM side
+    key, err := crypto.GenerateKey()
+    require.NoError(t, err)
+    deployer := crypto.PubkeyToAddress(key.PublicKey)
+    backend := simulated.NewBackend(types.GenesisAlloc{deployer: {Balance: big.NewInt(1e18)}})
+    t.Cleanup(func() { backend.Close() })
+    auth, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+    require.NoError(t, err)
+
+    implementation, _, _, err := bind.DeployContract(auth, abi.ABI{}, mockEscrowCode(), backend.Client())
+    require.NoError(t, err)
+    backend.Commit()
+    factory, _, _, err := bind.DeployContract(auth, abi.ABI{}, mockFactoryCode(implementation), backend.Client())
+    require.NoError(t, err)
+    backend.Commit()
+
+    store, err := OpenStore(filepath.Join(t.TempDir(), "relayer.db"))
+    require.NoError(t, err)
+    t.Cleanup(func() { store.Close() })
+
+    f := relayerFixture{
+        store:    store,
+        events:   chain.Subscribe(),
+        chain:    chain,
+        client:   client,
+        receiver: addrs[1],
+        backend:  backend,
+        auth:     auth,
+        factory:  factory,
+    }
+    escrowFactory := htlc.EscrowFactory{Chain: "ethereum", Address: factory.Hex(), SrcImplementation: implementation.Hex()}
+    f.relayer = NewRelayer(nil, client, NewEscrows(backend.Client(), escrowFactory, auth), store, "ethereum", time.Second, log.NewNopLogger())
+    return f
+}
+
+// escrow returns the source escrow address of immutables
+func (f relayerFixture) escrow(immutables Immutables) common.Address {
+    return f.relayer.escrows.SourceAddress(immutables)
+}
+
+// takerImmutables returns immutables with the relayer key as taker, deployed
+// at the EVM head time, whose private withdrawal opens after withdrawal
+func (f relayerFixture) takerImmutables(t *testing.T, secret [32]byte, withdrawal time.Duration) Immutables {
+    head, err := f.backend.Client().HeaderByNumber(context.Background(), nil)
+    require.NoError(t, err)
+    immutables := testImmutables(secret)
+    immutables.Taker = new(big.Int).SetBytes(f.auth.From.Bytes())
+    immutables.Timelocks = packTimelocks(head.Time, map[uint]time.Duration{
+        stageSrcWithdrawal:   withdrawal,
+        stageSrcCancellation: withdrawal + time.Hour,
+    })
+    return immutables
+}
+
+// packTimelocks packs per-stage offsets from deployedAt as TimelocksLib does
+func packTimelocks(deployedAt uint64, offsets map[uint]time.Duration) *big.Int {
+    timelocks := new(big.Int).Lsh(new(big.Int).SetUint64(deployedAt), 224)
+    for stage, offset := range offsets {
+        word := new(big.Int).Lsh(big.NewInt(int64(offset/time.Second)), 32*stage)
+        timelocks.Or(timelocks, word)
+    }
+    return timelocks
+}
+
+// announceEscrow makes the mock factory emit SrcEscrowCreated for immutables
+func (f relayerFixture) announceEscrow(t *testing.T, immutables Immutables) {
+    complement := struct {
+        Maker         *big.Int
+        Amount        *big.Int
+        Token         *big.Int
+        SafetyDeposit *big.Int
+        ChainId       *big.Int
+    }{big.NewInt(0), big.NewInt(100), big.NewInt(0), big.NewInt(0), big.NewInt(1)}
+    data, err := escrowABI.Events["SrcEscrowCreated"].Inputs.Pack(immutables, complement)
+    require.NoError(t, err)
+
+    contract := bind.NewBoundContract(f.factory, abi.ABI{}, f.backend.Client(), f.backend.Client(), f.backend.Client())
+    _, err = contract.RawTransact(f.auth, data)
+    require.NoError(t, err)
+    f.backend.Commit()
+}
+
+func testImmutables(secret [32]byte) Immutables {
+    return Immutables{
+        OrderHash:     [32]byte{1},
+        Hashlock:      evmHashlock(secret),
+        Maker:         big.NewInt(1),
+        Taker:         big.NewInt(2),
+        Token:         big.NewInt(0),
+        Amount:        big.NewInt(100),
+        SafetyDeposit: big.NewInt(0),
+        Timelocks:     big.NewInt(0),
+    }
+}
+
+func TestRelayer_ClaimIsRelayedToEscrow(t *testing.T) {
+    f := setupRelayer(t)
+    ctx := context.Background()
+    var secret [32]byte
+    copy(secret[:], bytes.Repeat([]byte{0xaa}, 32))
+    immutables := f.takerImmutables(t, secret, 0)
+    escrow := f.escrow(immutables)
+
+    id, err := f.client.Create(ctx, "sender", htlc.MsgCreateHTLC{
+        Receiver:      f.receiver,
+        Amount:        sdk.NewCoins(sdk.NewInt64Coin("atom", 100)),
+        HashLock:      sdk.Sha256(secret[:]),
+        TimeLock:      uint64(genesisTime.Add(2 * time.Hour).Unix()),
+        ExternalChain: "ethereum",
+        ExternalID:    escrow.Hex(),
+    })
+    require.NoError(t, err)
+    f.relayer.handleEvents(ctx, <-f.events)
+    swap, err := f.store.Swaps.Get(id)
+    require.NoError(t, err)
+    require.Equal(t, tracker.LegLocked, swap.Dest.State)
+    require.Equal(t, escrow.Hex(), swap.Source.ID)
+
+    // The claim reveals the secret before the relayer has seen the escrow
+    _, err = f.client.Claim(ctx, "receiver", id, secret[:], nil)
+    require.NoError(t, err)
+    f.relayer.handleEvents(ctx, <-f.events)
+    swap, err = f.store.Swaps.Get(id)
+    require.NoError(t, err)
+    require.Equal(t, tracker.LegClaimed, swap.Dest.State)
+    require.Equal(t, tracker.LegLocked, swap.Source.State)
+    require.Equal(t, secret[:], swap.Secret)
+
+    // The next tick learns the immutables from the factory and withdraws
+    f.announceEscrow(t, immutables)
+    f.relayer.tick(ctx)
+    swap, err = f.store.Swaps.Get(id)
+    require.NoError(t, err)
+    require.Equal(t, tracker.LegClaiming, swap.Source.State)
+
+    f.backend.Commit()
+    f.relayer.tick(ctx)
+    swap, err = f.store.Swaps.Get(id)
+    require.NoError(t, err)
+    require.Equal(t, tracker.LegClaimed, swap.Source.State)
+    require.Equal(t, tracker.SwapCompleted, swap.State)
+
+    logs, err := f.backend.Client().FilterLogs(ctx, ethereum.FilterQuery{
+        Addresses: []common.Address{escrow},
+        Topics:    [][]common.Hash{{escrowABI.Events["EscrowWithdrawal"].ID}},
+    })
+    require.NoError(t, err)
+    require.Len(t, logs, 1)
+    require.Equal(t, secret[:], logs[0].Data)
+
+    active, err := f.store.Swaps.Active()
+    require.NoError(t, err)
+    require.Empty(t, active)
+}
+
+func TestRelayer_ClaimWithPrunedSecret(t *testing.T) {
+    f := setupRelayer(t)
+    f.relayer.secretLookupTimeout = 20 * time.Millisecond
+    ctx := context.Background()
+    secret := bytes.Repeat([]byte{0xaa}, 32)
+
+    id, err := f.client.Create(ctx, "sender", htlc.MsgCreateHTLC{
+        Receiver:      f.receiver,
+        Amount:        sdk.NewCoins(sdk.NewInt64Coin("atom", 100)),
+        HashLock:      sdk.Sha256(secret),
+        TimeLock:      uint64(genesisTime.Add(2 * time.Hour).Unix()),
+        ExternalChain: "ethereum",
+        ExternalID:    common.BytesToAddress([]byte{0xe5}).Hex(),
+    })
+    require.NoError(t, err)
+    f.relayer.handleEvents(ctx, <-f.events)
+
+    // The secret is pruned before the relayer gets to the claim
+    _, err = f.client.Claim(ctx, "receiver", id, secret, nil)
+    require.NoError(t, err)
+    pruneCtx := f.chain.Context()
+    pruneCtx = pruneCtx.WithBlockHeight(pruneCtx.BlockHeight() + int64(htlc.DefaultRevealedSecretRetention) + 1)
+    f.chain.Keeper.PruneRevealedSecrets(pruneCtx)
+    require.False(t, f.chain.Keeper.HasRevealedSecret(f.chain.Context(), sdk.Sha256(secret)))
+
+    f.relayer.handleEvents(ctx, <-f.events)
+    swap, err := f.store.Swaps.Get(id)
+    require.NoError(t, err)
+    require.Equal(t, tracker.LegClaimed, swap.Dest.State)
+    require.True(t, swap.SecretMissing)
+    require.Equal(t, tracker.LegLocked, swap.Source.State)
+}
+
+// relaySwap creates an HTLC for the escrow of immutables, claims it and
+// announces the escrow, leaving the swap for the next tick to relay
+func (f relayerFixture) relaySwap(t *testing.T, secret [32]byte, immutables Immutables) string {
+    ctx := context.Background()
+    id, err := f.client.Create(ctx, "sender", htlc.MsgCreateHTLC{
+        Receiver:      f.receiver,
+        Amount:        sdk.NewCoins(sdk.NewInt64Coin("atom", 100)),
+        HashLock:      sdk.Sha256(secret[:]),
+        TimeLock:      uint64(genesisTime.Add(2 * time.Hour).Unix()),
+        ExternalChain: "ethereum",
+        ExternalID:    f.escrow(immutables).Hex(),
+    })
+    require.NoError(t, err)
+    f.relayer.handleEvents(ctx, <-f.events)
+    _, err = f.client.Claim(ctx, "receiver", id, secret[:], nil)
+    require.NoError(t, err)
+    f.relayer.handleEvents(ctx, <-f.events)
+    f.announceEscrow(t, immutables)
+    return id
+}
+
+func TestRelayer_WaitsForWithdrawalStage(t *testing.T) {
+    f := setupRelayer(t)
+    ctx := context.Background()
+    var secret [32]byte
+    copy(secret[:], bytes.Repeat([]byte{0xab}, 32))
+    immutables := f.takerImmutables(t, secret, 10*time.Minute)
+    id := f.relaySwap(t, secret, immutables)
+
+    // Nothing is sent before the withdrawal stage opens
+    f.relayer.tick(ctx)
+    swap, err := f.store.Swaps.Get(id)
+    require.NoError(t, err)
+    require.Equal(t, tracker.LegLocked, swap.Source.State)
+    require.Empty(t, swap.Source.TxHash)
+
+    // A withdrawal sent anyway reverts in the escrow's onlyAfter
+    opts := *f.auth
+    opts.GasLimit = 100_000
+    contract := bind.NewBoundContract(f.escrow(immutables), escrowABI, f.backend.Client(), f.backend.Client(), f.backend.Client())
+    tx, err := contract.Transact(&opts, "withdraw", secret, immutables)
+    require.NoError(t, err)
+    f.backend.Commit()
+    receipt, err := f.backend.Client().TransactionReceipt(ctx, tx.Hash())
+    require.NoError(t, err)
+    require.Equal(t, types.ReceiptStatusFailed, receipt.Status)
+
+    // The revert is retried rather than failing the swap
+    require.NoError(t, f.store.Swaps.Update(id, func(swap *tracker.Swap) error {
+        swap.Source.State, swap.Source.TxHash = tracker.LegClaiming, tx.Hash().Hex()
+        return nil
+    }))
+    f.relayer.tick(ctx)
+    swap, err = f.store.Swaps.Get(id)
+    require.NoError(t, err)
+    require.Equal(t, tracker.LegLocked, swap.Source.State)
+    require.Equal(t, tracker.SwapActive, swap.State)
+
+    require.NoError(t, f.backend.AdjustTime(10*time.Minute))
+    f.backend.Commit()
+    f.relayer.tick(ctx)
+    swap, err = f.store.Swaps.Get(id)
+    require.NoError(t, err)
+    require.Equal(t, tracker.LegClaiming, swap.Source.State)
+
+    f.backend.Commit()
+    f.relayer.tick(ctx)
+    swap, err = f.store.Swaps.Get(id)
+    require.NoError(t, err)
+    require.Equal(t, tracker.SwapCompleted, swap.State)
+}
+
+func TestRelayer_FailsSwapOfOtherTaker(t *testing.T) {
+    f := setupRelayer(t)
+    ctx := context.Background()
+    var secret [32]byte
+    copy(secret[:], bytes.Repeat([]byte{0xac}, 32))
+    immutables := f.takerImmutables(t, secret, 0)
+    immutables.Taker = big.NewInt(2)
+    id := f.relaySwap(t, secret, immutables)
+
+    nonce, err := f.backend.Client().PendingNonceAt(ctx, f.auth.From)
+    require.NoError(t, err)
+    f.relayer.tick(ctx)
+    swap, err := f.store.Swaps.Get(id)
+    require.NoError(t, err)
+    require.Equal(t, tracker.SwapFailed, swap.State)
+    require.Contains(t, swap.Error, "taker")
+    require.Empty(t, swap.Source.TxHash)
+
+    after, err := f.backend.Client().PendingNonceAt(ctx, f.auth.From)
+    require.NoError(t, err)
+    require.Equal(t, nonce, after)
+}
+
+func TestEscrows_DryRunRevertReason(t *testing.T) {
+    f := setupRelayer(t)
+    ctx := context.Background()
+    var secret [32]byte
+    immutables := f.takerImmutables(t, secret, 0)
+    f.announceEscrow(t, immutables)
+    escrow := f.escrow(immutables)
+    data, err := escrowABI.Pack("withdraw", secret, immutables)
+    require.NoError(t, err)
+
+    // Only the taker may withdraw
+    _, err = f.backend.Client().CallContract(ctx, ethereum.CallMsg{From: common.Address{1}, To: &escrow, Data: data}, nil)
+    reason, reverted := revertReason(err)
+    require.True(t, reverted)
+    require.Equal(t, "InvalidCaller", reason)
+
+    _, err = f.backend.Client().CallContract(ctx, ethereum.CallMsg{From: f.auth.From, To: &escrow, Data: data}, nil)
+    require.NoError(t, err)
+    require.NoError(t, f.relayer.escrows.CheckWithdraw(ctx, escrow, secret, immutables))
+
+    // Another escrow's address is rejected before any call
+    err = f.relayer.escrows.CheckWithdraw(ctx, f.factory, secret, immutables)
+    require.ErrorIs(t, err, errCannotWithdraw)
+}
+
+func TestRelayer_IgnoresOtherChains(t *testing.T) {
+    f := setupRelayer(t)
+    ctx := context.Background()
+
+    id, err := f.client.Create(ctx, "sender", htlc.MsgCreateHTLC{
+        Receiver:      f.receiver,
+        Amount:        sdk.NewCoins(sdk.NewInt64Coin("atom", 100)),
+        HashLock:      sdk.Sha256(bytes.Repeat([]byte{0xbb}, 32)),
+        TimeLock:      uint64(genesisTime.Add(2 * time.Hour).Unix()),
+        ExternalChain: "bitcoin",
+        ExternalID:    "f4184fc596403b9d638783cf57adfe4c75c605f6356fbc91338530e9831e9e16",
+    })
+    require.NoError(t, err)
+    f.relayer.handleEvents(ctx, <-f.events)
+
+    _, err = f.store.Swaps.Get(id)
+    require.ErrorIs(t, err, tracker.ErrNotFound)
+}
+
+func TestStore_SurvivesRestart(t *testing.T) {
+    path := filepath.Join(t.TempDir(), "relayer.db")
+    store, err := OpenStore(path)
+    require.NoError(t, err)
+
+    var secret [32]byte
+    secret[0] = 0xcc
+    require.NoError(t, store.PutImmutables(testImmutables(secret)))
+    require.NoError(t, store.SetEVMCursor(42))
+    require.NoError(t, store.Swaps.Put(tracker.Swap{ID: "a", Secret: secret[:]}))
+    require.NoError(t, store.Close())
+
+    store, err = OpenStore(path)
+    require.NoError(t, err)
+    defer store.Close()
+
+    active, err := store.Swaps.Active()
+    require.NoError(t, err)
+    require.Len(t, active, 1)
+    require.Equal(t, secret[:], active[0].Secret)
+
+    immutables, found, err := store.GetImmutables(evmHashlock(secret))
+    require.NoError(t, err)
+    require.True(t, found)
+    require.Equal(t, int64(100), immutables.Amount.Int64())
+
+    cursor, err := store.EVMCursor()
+    require.NoError(t, err)
+    require.Equal(t, uint64(42), cursor)
+}
@@ -0,0 +1,100 @@
+// cmd/htlc-relayer/store.go
+package main
+
+import (
+    "encoding/binary"
+    "encoding/json"
+
+    bolt "go.etcd.io/bbolt"
+
+    "github.com/your_repo/tracker"
+)
+
+var (
+    immutablesBucket = []byte("immutables")
+    metaBucket       = []byte("meta")
+
+    evmCursorKey = []byte("evm_block")
+)
+
+// Store keeps the relayer state in one bbolt database: the tracked swaps,
+// escrow immutables and the EVM log cursor, so the relayer picks up where
+// it stopped after a restart
+type Store struct {
+    db    *bolt.DB
+    Swaps *tracker.Tracker
+}
+
+func OpenStore(path string) (*Store, error) {
+    db, err := bolt.Open(path, 0o600, nil)
+    if err != nil {
+        return nil, err
+    }
+    err = db.Update(func(tx *bolt.Tx) error {
+        for _, bucket := range [][]byte{immutablesBucket, metaBucket} {
+            if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+                return err
+            }
+        }
+        return nil
+    })
+    if err != nil {
+        db.Close()
+        return nil, err
+    }
+    swaps, err := tracker.New(db)
+    if err != nil {
+        db.Close()
+        return nil, err
+    }
+    return &Store{db: db, Swaps: swaps}, nil
+}
+
+func (s *Store) Close() error {
+    return s.db.Close()
+}
+
+// GetImmutables returns the escrow immutables recorded for an EVM hashlock
+func (s *Store) GetImmutables(hashlock [32]byte) (Immutables, bool, error) {
+    var immutables Immutables
+    var found bool
+    err := s.db.View(func(tx *bolt.Tx) error {
+        bz := tx.Bucket(immutablesBucket).Get(hashlock[:])
+        if bz == nil {
+            return nil
+        }
+        found = true
+        return json.Unmarshal(bz, &immutables)
+    })
+    return immutables, found, err
+}
+
+func (s *Store) PutImmutables(immutables Immutables) error {
+    bz, err := json.Marshal(immutables)
+    if err != nil {
+        return err
+    }
+    return s.db.Update(func(tx *bolt.Tx) error {
+        return tx.Bucket(immutablesBucket).Put(immutables.Hashlock[:], bz)
+    })
+}
+
+// EVMCursor returns the next EVM block to scan for factory logs
+func (s *Store) EVMCursor() (uint64, error) {
+    var block uint64
+    err := s.db.View(func(tx *bolt.Tx) error {
+        if bz := tx.Bucket(metaBucket).Get(evmCursorKey); bz != nil {
+            block = binary.BigEndian.Uint64(bz)
+        }
+        return nil
+    })
+    return block, err
+}
+
+func (s *Store) SetEVMCursor(block uint64) error {
+    bz := make([]byte, 8)
+    binary.BigEndian.PutUint64(bz, block)
+    return s.db.Update(func(tx *bolt.Tx) error {
+        return tx.Bucket(metaBucket).Put(evmCursorKey, bz)
+    })
+}
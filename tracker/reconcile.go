@@ -0,0 +1,145 @@
+// tracker/reconcile.go
+package tracker
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "time"
+
+    "github.com/your_repo/x/htlc"
+    htlcclient "github.com/your_repo/x/htlc/client"
+)
+
+// secretLookupTimeout bounds the wait for the revealed secret of a claimed
+// HTLC. It is normally returned at once, a lookup running into the timeout
+// means the secret was already pruned.
+const secretLookupTimeout = 5 * time.Second
+
+// HTLCQuerier reads HTLCs from the chain, *htlcclient.HTLCClient implements it
+type HTLCQuerier interface {
+    Get(ctx context.Context, id string) (htlc.HTLC, error)
+    List(ctx context.Context, filter htlcclient.ListFilter) ([]htlc.HTLC, error)
+    WaitForClaim(ctx context.Context, hashLock []byte) (htlc.RevealedSecret, error)
+}
+
+// Reconcile brings the Cosmos leg of every active swap in line with the
+// chain, e.g. after a restart during which events were missed. Pending legs
+// are matched to an HTLC by hashlock, secrets of claimed HTLCs are recorded.
+// It returns the swaps that changed.
+func (t *Tracker) Reconcile(ctx context.Context, q HTLCQuerier) ([]Swap, error) {
+    swaps, err := t.Active()
+    if err != nil {
+        return nil, err
+    }
+
+    var changed []Swap
+    var errs []error
+    for _, swap := range swaps {
+        updated, err := reconcileSwap(ctx, q, swap)
+        if err != nil {
+            errs = append(errs, fmt.Errorf("swap %s: %w", swap.ID, err))
+            continue
+        }
+        if updated == nil {
+            continue
+        }
+        if err := t.Put(*updated); err != nil {
+            return changed, err
+        }
+        changed = append(changed, *updated)
+    }
+    return changed, errors.Join(errs...)
+}
+
+// reconcileSwap returns the updated swap, or nil if nothing changed
+func reconcileSwap(ctx context.Context, q HTLCQuerier, swap Swap) (*Swap, error) {
+    leg := swap.CosmosLeg()
+    if leg == nil {
+        return nil, nil
+    }
+    before := *leg
+    hadSecret := len(swap.Secret) > 0
+    wasMissing := swap.SecretMissing
+
+    h, found, err := findHTLC(ctx, q, *leg)
+    if err != nil {
+        return nil, err
+    }
+    if !found {
+        if leg.State == LegPending {
+            return nil, nil
+        }
+        leg.State = LegFailed
+        swap.State = SwapFailed
+        swap.Error = "htlc not found on chain"
+        return &swap, nil
+    }
+
+    leg.ID = h.ID
+    switch {
+    case h.Claimed:
+        leg.State = LegClaimed
+        if !hadSecret && !wasMissing {
+            lookupCtx, cancel := context.WithTimeout(ctx, secretLookupTimeout)
+            revealed, err := q.WaitForClaim(lookupCtx, h.HashLock)
+            cancel()
+            switch {
+            case err == nil:
+                if !leg.VerifySecret(revealed.Secret) {
+                    return nil, ErrInvalidSecret
+                }
+                swap.Secret = revealed.Secret
+            case errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil:
+                // The leg is claimed all the same, record that and flag the
+                // secret instead of retrying the lookup on every pass
+                swap.SecretMissing = true
+            default:
+                return nil, fmt.Errorf("secret of claimed htlc %s: %w", h.ID, err)
+            }
+        }
+    case h.Refunded, h.Rescued:
+        leg.State = LegRefunded
+    case leg.State == LegPending:
+        leg.State = LegLocked
+    }
+    if leg.Deadline.IsZero() {
+        leg.Deadline = h.TimeLock
+    }
+
+    if leg.ID == before.ID && leg.State == before.State && leg.Deadline.Equal(before.Deadline) && hadSecret == (len(swap.Secret) > 0) && wasMissing == swap.SecretMissing {
+        return nil, nil
+    }
+    swap.Settle()
+    return &swap, nil
+}
+
+// findHTLC loads the HTLC of leg by ID, or by hashlock while the ID is unknown
+func findHTLC(ctx context.Context, q HTLCQuerier, leg Leg) (htlc.HTLC, bool, error) {
+    if leg.ID != "" {
+        h, err := q.Get(ctx, leg.ID)
+        if errors.Is(err, htlc.ErrHTLCNotFound) {
+            return h, false, nil
+        }
+        return h, err == nil, err
+    }
+    if len(leg.HashLock) == 0 {
+        return htlc.HTLC{}, false, nil
+    }
+
+    htlcs, err := q.List(ctx, htlcclient.ListFilter{HashLock: leg.HashLock})
+    if err != nil {
+        return htlc.HTLC{}, false, err
+    }
+    // Prefer an HTLC still holding funds, a hashlock may be reused once the
+    // previous HTLC settled
+    for _, h := range htlcs {
+        if h.IsOpen() {
+            return h, true, nil
+        }
+    }
+    if len(htlcs) > 0 {
+        return htlcs[len(htlcs)-1], true, nil
+    }
+    return htlc.HTLC{}, false, nil
+}
@@ -0,0 +1,125 @@
+// tracker/swap.go
+package tracker
+
+import (
+    "bytes"
+    "crypto/sha256"
+    "time"
+
+    "golang.org/x/crypto/sha3"
+)
+
+// CosmosChain is the Chain of legs locked in the htlc module
+const CosmosChain = "cosmos"
+
+// LegState is the state of the funds locked on one chain
+type LegState string
+
+const (
+    // LegPending means the leg is expected but not locked yet
+    LegPending LegState = "pending"
+    // LegLocked means the funds are locked and can be claimed with the secret
+    LegLocked LegState = "locked"
+    // LegClaiming means a claim tx was sent and is not confirmed yet
+    LegClaiming LegState = "claiming"
+    LegClaimed  LegState = "claimed"
+    LegRefunded LegState = "refunded"
+    LegFailed   LegState = "failed"
+)
+
+// Settled reports whether the funds of the leg have left the lock
+func (s LegState) Settled() bool {
+    return s == LegClaimed || s == LegRefunded
+}
+
+// SwapState is the overall state of a swap
+type SwapState string
+
+const (
+    SwapActive    SwapState = "active"
+    SwapCompleted SwapState = "completed"
+    SwapCancelled SwapState = "cancelled"
+    SwapFailed    SwapState = "failed"
+)
+
+// Final reports whether nothing is left to do for the swap
+func (s SwapState) Final() bool {
+    return s == SwapCompleted || s == SwapCancelled || s == SwapFailed
+}
+
+// Leg is the lock of one side of a swap
+type Leg struct {
+    Chain string `json:"chain"`
+    // ID is the HTLC ID on the Cosmos chain and the escrow address on EVM
+    // chains. It may be empty while the leg is pending.
+    ID       string    `json:"id"`
+    HashLock []byte    `json:"hashlock,omitempty"`
+    Amount   string    `json:"amount,omitempty"`
+    Deadline time.Time `json:"deadline"`
+    State    LegState  `json:"state"`
+    // TxHash is the last claim or refund tx sent for the leg
+    TxHash string `json:"tx_hash,omitempty"`
+}
+
+// VerifySecret checks secret against the leg hashlock. The htlc module locks
+// with sha256, EVM escrows with keccak256.
+func (l Leg) VerifySecret(secret []byte) bool {
+    if len(l.HashLock) == 0 {
+        return false
+    }
+    if l.Chain == CosmosChain {
+        sum := sha256.Sum256(secret)
+        return bytes.Equal(sum[:], l.HashLock)
+    }
+    hasher := sha3.NewLegacyKeccak256()
+    hasher.Write(secret)
+    return bytes.Equal(hasher.Sum(nil), l.HashLock)
+}
+
+// Swap is a cross-chain swap made of the leg the maker locks (Source) and
+// the leg the taker locks in return (Dest), unlocked by the same secret
+type Swap struct {
+    ID            string    `json:"id"`
+    Source        Leg       `json:"source"`
+    Dest          Leg       `json:"dest"`
+    Secret        []byte    `json:"secret,omitempty"`
+    // SecretMissing is set when the Cosmos leg was claimed but the chain had
+    // already pruned its secret, which then has to come from the other chain
+    SecretMissing bool      `json:"secret_missing,omitempty"`
+    State         SwapState `json:"state"`
+    Error         string    `json:"error,omitempty"`
+}
+
+// CosmosLeg returns the leg locked in the htlc module, or nil
+func (s *Swap) CosmosLeg() *Leg {
+    switch CosmosChain {
+    case s.Source.Chain:
+        return &s.Source
+    case s.Dest.Chain:
+        return &s.Dest
+    }
+    return nil
+}
+
+// Settle moves an active swap to its final state once both legs settled:
+// completed when both were claimed, cancelled otherwise
+func (s *Swap) Settle() {
+    if s.State.Final() || !s.Source.State.Settled() || !s.Dest.State.Settled() {
+        return
+    }
+    if s.Source.State == LegClaimed && s.Dest.State == LegClaimed {
+        s.State = SwapCompleted
+    } else {
+        s.State = SwapCancelled
+    }
+}
+
+// Expired reports whether a leg still holding funds is past its deadline
+func (s Swap) Expired(now time.Time) bool {
+    for _, leg := range []Leg{s.Source, s.Dest} {
+        if leg.State == LegLocked && !leg.Deadline.IsZero() && !now.Before(leg.Deadline) {
+            return true
+        }
+    }
+    return false
+}
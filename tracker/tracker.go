@@ -0,0 +1,158 @@
+// tracker/tracker.go
+package tracker
+
+import (
+    "encoding/json"
+    "errors"
+    "fmt"
+    "time"
+
+    bolt "go.etcd.io/bbolt"
+)
+
+var swapsBucket = []byte("tracker_swaps")
+
+var (
+    ErrNotFound      = errors.New("swap not found")
+    ErrInvalidSecret = errors.New("secret does not match the swap hashlocks")
+)
+
+// Tracker persists swaps in a bbolt database so in-flight swaps and their
+// secrets survive restarts
+type Tracker struct {
+    db    *bolt.DB
+    owned bool
+}
+
+// Open opens or creates the tracker database at path
+func Open(path string) (*Tracker, error) {
+    db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+    if err != nil {
+        return nil, err
+    }
+    t, err := New(db)
+    if err != nil {
+        db.Close()
+        return nil, err
+    }
+    t.owned = true
+    return t, nil
+}
+
+// New keeps swaps in a bucket of an already open database, which stays
+// owned by the caller
+func New(db *bolt.DB) (*Tracker, error) {
+    err := db.Update(func(tx *bolt.Tx) error {
+        _, err := tx.CreateBucketIfNotExists(swapsBucket)
+        return err
+    })
+    if err != nil {
+        return nil, err
+    }
+    return &Tracker{db: db}, nil
+}
+
+// Close closes the database if it was opened by Open
+func (t *Tracker) Close() error {
+    if !t.owned {
+        return nil
+    }
+    return t.db.Close()
+}
+
+// Put stores swap, replacing any swap with the same ID
+func (t *Tracker) Put(swap Swap) error {
+    if swap.ID == "" {
+        return errors.New("swap ID is empty")
+    }
+    if swap.State == "" {
+        swap.State = SwapActive
+    }
+    return t.db.Update(func(tx *bolt.Tx) error {
+        return putSwap(tx, swap)
+    })
+}
+
+func (t *Tracker) Get(id string) (Swap, error) {
+    var swap Swap
+    err := t.db.View(func(tx *bolt.Tx) error {
+        var err error
+        swap, err = getSwap(tx, id)
+        return err
+    })
+    return swap, err
+}
+
+// Update applies fn to the stored swap atomically. Nothing is written when
+// fn returns an error.
+func (t *Tracker) Update(id string, fn func(*Swap) error) error {
+    return t.db.Update(func(tx *bolt.Tx) error {
+        swap, err := getSwap(tx, id)
+        if err != nil {
+            return err
+        }
+        if err := fn(&swap); err != nil {
+            return err
+        }
+        return putSwap(tx, swap)
+    })
+}
+
+// RevealSecret records the secret of a swap after checking it against the
+// hashlock of at least one leg
+func (t *Tracker) RevealSecret(id string, secret []byte) error {
+    return t.Update(id, func(swap *Swap) error {
+        if !swap.Source.VerifySecret(secret) && !swap.Dest.VerifySecret(secret) {
+            return ErrInvalidSecret
+        }
+        swap.Secret = append([]byte{}, secret...)
+        swap.SecretMissing = false
+        return nil
+    })
+}
+
+// Active returns the swaps not in a final state
+func (t *Tracker) Active() ([]Swap, error) {
+    return t.filter(func(swap Swap) bool { return !swap.State.Final() })
+}
+
+// Expired returns the active swaps with a leg past its deadline and still
+// locked, i.e. the swaps to refund
+func (t *Tracker) Expired(now time.Time) ([]Swap, error) {
+    return t.filter(func(swap Swap) bool { return !swap.State.Final() && swap.Expired(now) })
+}
+
+func (t *Tracker) filter(keep func(Swap) bool) ([]Swap, error) {
+    var swaps []Swap
+    err := t.db.View(func(tx *bolt.Tx) error {
+        return tx.Bucket(swapsBucket).ForEach(func(_, bz []byte) error {
+            var swap Swap
+            if err := json.Unmarshal(bz, &swap); err != nil {
+                return err
+            }
+            if keep(swap) {
+                swaps = append(swaps, swap)
+            }
+            return nil
+        })
+    })
+    return swaps, err
+}
+
+func getSwap(tx *bolt.Tx, id string) (Swap, error) {
+    var swap Swap
+    bz := tx.Bucket(swapsBucket).Get([]byte(id))
+    if bz == nil {
+        return swap, fmt.Errorf("%w: %s", ErrNotFound, id)
+    }
+    err := json.Unmarshal(bz, &swap)
+    return swap, err
+}
+
+func putSwap(tx *bolt.Tx, swap Swap) error {
+    bz, err := json.Marshal(swap)
+    if err != nil {
+        return err
+    }
+    return tx.Bucket(swapsBucket).Put([]byte(swap.ID), bz)
+}
@@ -0,0 +1,244 @@
+// tracker/tracker_test.go
+package tracker_test
+
+import (
+    "bytes"
+    "context"
+    "path/filepath"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/require"
+    "golang.org/x/crypto/sha3"
+
+    "github.com/cosmos/cosmos-sdk/codec"
+    codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+    cryptocodec "github.com/cosmos/cosmos-sdk/crypto/codec"
+    "github.com/cosmos/cosmos-sdk/crypto/hd"
+    "github.com/cosmos/cosmos-sdk/crypto/keyring"
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    authtx "github.com/cosmos/cosmos-sdk/x/auth/tx"
+
+    "github.com/your_repo/tracker"
+    "github.com/your_repo/x/htlc"
+    htlcclient "github.com/your_repo/x/htlc/client"
+    "github.com/your_repo/x/htlc/testutil"
+)
+
+var genesisTime = time.Unix(1_700_000_000, 0).UTC()
+
+func keccak(b []byte) []byte {
+    hasher := sha3.NewLegacyKeccak256()
+    hasher.Write(b)
+    return hasher.Sum(nil)
+}
+
+func newSwap(id string, secret []byte) tracker.Swap {
+    return tracker.Swap{
+        ID: id,
+        Source: tracker.Leg{
+            Chain:    "ethereum",
+            ID:       "0x00000000000000000000000000000000000000e5",
+            HashLock: keccak(secret),
+            Deadline: genesisTime.Add(4 * time.Hour),
+            State:    tracker.LegLocked,
+        },
+        Dest: tracker.Leg{
+            Chain:    tracker.CosmosChain,
+            HashLock: sdk.Sha256(secret),
+            Deadline: genesisTime.Add(2 * time.Hour),
+            State:    tracker.LegPending,
+        },
+    }
+}
+
+func TestTracker_PersistsAcrossRestart(t *testing.T) {
+    path := filepath.Join(t.TempDir(), "tracker.db")
+    tr, err := tracker.Open(path)
+    require.NoError(t, err)
+
+    secret := bytes.Repeat([]byte{0xaa}, 32)
+    require.NoError(t, tr.Put(newSwap("swap-1", secret)))
+    done := newSwap("swap-2", bytes.Repeat([]byte{0xbb}, 32))
+    done.State = tracker.SwapCompleted
+    require.NoError(t, tr.Put(done))
+
+    require.ErrorIs(t, tr.RevealSecret("swap-1", bytes.Repeat([]byte{0x01}, 32)), tracker.ErrInvalidSecret)
+    require.NoError(t, tr.RevealSecret("swap-1", secret))
+    require.ErrorIs(t, tr.RevealSecret("missing", secret), tracker.ErrNotFound)
+    require.NoError(t, tr.Close())
+
+    tr, err = tracker.Open(path)
+    require.NoError(t, err)
+    defer tr.Close()
+
+    active, err := tr.Active()
+    require.NoError(t, err)
+    require.Len(t, active, 1)
+    require.Equal(t, "swap-1", active[0].ID)
+    require.Equal(t, tracker.SwapActive, active[0].State)
+    require.Equal(t, secret, active[0].Secret)
+}
+
+func TestTracker_Expired(t *testing.T) {
+    tr, err := tracker.Open(filepath.Join(t.TempDir(), "tracker.db"))
+    require.NoError(t, err)
+    defer tr.Close()
+
+    swap := newSwap("swap-1", bytes.Repeat([]byte{0xaa}, 32))
+    require.NoError(t, tr.Put(swap))
+
+    // The pending Cosmos leg has nothing to refund, the source leg expires at its deadline
+    expired, err := tr.Expired(swap.Source.Deadline.Add(-time.Second))
+    require.NoError(t, err)
+    require.Empty(t, expired)
+    expired, err = tr.Expired(swap.Source.Deadline)
+    require.NoError(t, err)
+    require.Len(t, expired, 1)
+
+    require.NoError(t, tr.Update("swap-1", func(s *tracker.Swap) error {
+        s.Source.State = tracker.LegRefunded
+        s.Dest.State = tracker.LegRefunded
+        s.Settle()
+        return nil
+    }))
+    got, err := tr.Get("swap-1")
+    require.NoError(t, err)
+    require.Equal(t, tracker.SwapCancelled, got.State)
+}
+
+func setupChain(t *testing.T) (*testutil.MockChain, *htlcclient.HTLCClient, sdk.AccAddress) {
+    registry := codectypes.NewInterfaceRegistry()
+    cryptocodec.RegisterInterfaces(registry)
+    htlc.RegisterInterfaces(registry)
+    cdc := codec.NewProtoCodec(registry)
+    txConfig := authtx.NewTxConfig(cdc, authtx.DefaultSignModes)
+    kr := keyring.NewInMemory(cdc)
+    var addrs []sdk.AccAddress
+    for _, name := range []string{"sender", "receiver"} {
+        record, _, err := kr.NewMnemonic(name, keyring.English, sdk.FullFundraiserPath, keyring.DefaultBIP39Passphrase, hd.Secp256k1)
+        require.NoError(t, err)
+        addr, err := record.GetAddress()
+        require.NoError(t, err)
+        addrs = append(addrs, addr)
+    }
+
+    chain := testutil.NewMockChain(t, txConfig.TxDecoder(), genesisTime)
+    chain.Bank.FundAccount(addrs[0], sdk.NewCoins(sdk.NewInt64Coin("atom", 1000)))
+    cfg := htlcclient.DefaultConfig("htlc-test")
+    cfg.PollInterval = time.Millisecond
+    return chain, htlcclient.NewHTLCClient(chain, txConfig, kr, cfg), addrs[1]
+}
+
+func TestTracker_ReconcileAfterRestart(t *testing.T) {
+    ctx := context.Background()
+    _, client, receiver := setupChain(t)
+    path := filepath.Join(t.TempDir(), "tracker.db")
+    secret := bytes.Repeat([]byte{0xaa}, 32)
+
+    tr, err := tracker.Open(path)
+    require.NoError(t, err)
+    require.NoError(t, tr.Put(newSwap("swap-1", secret)))
+    require.NoError(t, tr.Close())
+
+    // While the tracker is down the taker locks and the maker claims
+    id, err := client.Create(ctx, "sender", htlc.MsgCreateHTLC{
+        Receiver: receiver,
+        Amount:   sdk.NewCoins(sdk.NewInt64Coin("atom", 100)),
+        HashLock: sdk.Sha256(secret),
+        TimeLock: uint64(genesisTime.Add(2 * time.Hour).Unix()),
+    })
+    require.NoError(t, err)
+    _, err = client.Claim(ctx, "receiver", id, secret, nil)
+    require.NoError(t, err)
+
+    tr, err = tracker.Open(path)
+    require.NoError(t, err)
+    defer tr.Close()
+
+    changed, err := tr.Reconcile(ctx, client)
+    require.NoError(t, err)
+    require.Len(t, changed, 1)
+
+    swap, err := tr.Get("swap-1")
+    require.NoError(t, err)
+    require.Equal(t, id, swap.Dest.ID)
+    require.Equal(t, tracker.LegClaimed, swap.Dest.State)
+    require.Equal(t, secret, swap.Secret)
+    // The source leg still has to be withdrawn with the secret
+    require.Equal(t, tracker.SwapActive, swap.State)
+
+    // A second pass finds nothing new
+    changed, err = tr.Reconcile(ctx, client)
+    require.NoError(t, err)
+    require.Empty(t, changed)
+}
+
+// prunedSecretQuerier never finds a revealed secret, like a chain that
+// already pruned it
+type prunedSecretQuerier struct {
+    *htlcclient.HTLCClient
+}
+
+func (prunedSecretQuerier) WaitForClaim(context.Context, []byte) (htlc.RevealedSecret, error) {
+    return htlc.RevealedSecret{}, context.DeadlineExceeded
+}
+
+func TestTracker_ReconcilePrunedSecret(t *testing.T) {
+    ctx := context.Background()
+    _, client, receiver := setupChain(t)
+    tr, err := tracker.Open(filepath.Join(t.TempDir(), "tracker.db"))
+    require.NoError(t, err)
+    defer tr.Close()
+
+    secret := bytes.Repeat([]byte{0xaa}, 32)
+    require.NoError(t, tr.Put(newSwap("swap-1", secret)))
+    id, err := client.Create(ctx, "sender", htlc.MsgCreateHTLC{
+        Receiver: receiver,
+        Amount:   sdk.NewCoins(sdk.NewInt64Coin("atom", 100)),
+        HashLock: sdk.Sha256(secret),
+        TimeLock: uint64(genesisTime.Add(2 * time.Hour).Unix()),
+    })
+    require.NoError(t, err)
+    _, err = client.Claim(ctx, "receiver", id, secret, nil)
+    require.NoError(t, err)
+
+    changed, err := tr.Reconcile(ctx, prunedSecretQuerier{client})
+    require.NoError(t, err)
+    require.Len(t, changed, 1)
+
+    swap, err := tr.Get("swap-1")
+    require.NoError(t, err)
+    require.Equal(t, tracker.LegClaimed, swap.Dest.State)
+    require.True(t, swap.SecretMissing)
+    require.Empty(t, swap.Secret)
+
+    // The lookup isn't retried, and the secret can still come from elsewhere
+    changed, err = tr.Reconcile(ctx, prunedSecretQuerier{client})
+    require.NoError(t, err)
+    require.Empty(t, changed)
+    require.NoError(t, tr.RevealSecret("swap-1", secret))
+    swap, err = tr.Get("swap-1")
+    require.NoError(t, err)
+    require.False(t, swap.SecretMissing)
+}
+
+func TestTracker_ReconcileMissingHTLC(t *testing.T) {
+    ctx := context.Background()
+    _, client, _ := setupChain(t)
+    tr, err := tracker.Open(filepath.Join(t.TempDir(), "tracker.db"))
+    require.NoError(t, err)
+    defer tr.Close()
+
+    swap := newSwap("swap-1", bytes.Repeat([]byte{0xaa}, 32))
+    swap.Dest.ID = "unknown-1700000000"
+    swap.Dest.State = tracker.LegLocked
+    require.NoError(t, tr.Put(swap))
+
+    _, err = tr.Reconcile(ctx, client)
+    require.NoError(t, err)
+    got, err := tr.Get("swap-1")
+    require.NoError(t, err)
+    require.Equal(t, tracker.SwapFailed, got.State)
+    require.Equal(t, tracker.LegFailed, got.Dest.State)
+}
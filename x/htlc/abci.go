@@ -0,0 +1,11 @@
+// x/htlc/abci.go
+package htlc
+
+import (
+    sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// EndBlocker prunes revealed secrets past their retention window
+func EndBlocker(ctx sdk.Context, k Keeper) {
+    k.PruneRevealedSecrets(ctx)
+}
@@ -0,0 +1,97 @@
+// x/htlc/authz.go
+package htlc
+
+import (
+    "context"
+
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+    "github.com/cosmos/cosmos-sdk/x/authz"
+)
+
+var _ authz.Authorization = &HTLCAuthorization{}
+
+func NewHTLCAuthorization(msgType string, allowedDenoms []string, maxAmount sdk.Coins, allowedExternalChains []string) *HTLCAuthorization {
+    return &HTLCAuthorization{
+        MsgType:               msgType,
+        AllowedDenoms:         allowedDenoms,
+        MaxAmount:             maxAmount,
+        AllowedExternalChains: allowedExternalChains,
+    }
+}
+
+func (a HTLCAuthorization) MsgTypeURL() string {
+    return a.MsgType
+}
+
+func (a HTLCAuthorization) ValidateBasic() error {
+    switch a.MsgType {
+    case TypeURLMsgCreateHTLC, TypeURLMsgClaimHTLC, TypeURLMsgRefundHTLC:
+    default:
+        return sdkerrors.Wrapf(sdkerrors.ErrInvalidType, "unsupported htlc msg type %s", a.MsgType)
+    }
+    if !a.MaxAmount.IsValid() {
+        return sdkerrors.Wrap(sdkerrors.ErrInvalidCoins, "invalid max amount")
+    }
+    return nil
+}
+
+// Accept checks the wrapped message against the grant limits. Create
+// messages consume MaxAmount; claims may not redirect coins away from the
+// granter, so a grantee can settle but never take the funds.
+func (a HTLCAuthorization) Accept(_ context.Context, msg sdk.Msg) (authz.AcceptResponse, error) {
+    switch msg := msg.(type) {
+    case *MsgCreateHTLC:
+        return a.acceptCreate(*msg)
+    case *MsgClaimHTLC:
+        if !msg.Target.Empty() && !msg.Target.Equals(msg.Claimer) {
+            return authz.AcceptResponse{}, sdkerrors.Wrap(sdkerrors.ErrUnauthorized, "grantee cannot redirect claimed coins")
+        }
+        return authz.AcceptResponse{Accept: a.MsgType == TypeURLMsgClaimHTLC}, nil
+    case *MsgRefundHTLC:
+        return authz.AcceptResponse{Accept: a.MsgType == TypeURLMsgRefundHTLC}, nil
+    default:
+        return authz.AcceptResponse{}, sdkerrors.Wrapf(sdkerrors.ErrInvalidType, "unexpected msg type %T", msg)
+    }
+}
+
+func (a HTLCAuthorization) acceptCreate(msg MsgCreateHTLC) (authz.AcceptResponse, error) {
+    if a.MsgType != TypeURLMsgCreateHTLC {
+        return authz.AcceptResponse{}, sdkerrors.Wrapf(sdkerrors.ErrInvalidType, "authorization is for %s", a.MsgType)
+    }
+
+    if len(a.AllowedDenoms) > 0 {
+        for _, coin := range msg.Amount {
+            if !containsString(a.AllowedDenoms, coin.Denom) {
+                return authz.AcceptResponse{}, sdkerrors.Wrapf(sdkerrors.ErrUnauthorized, "denom %s not allowed", coin.Denom)
+            }
+        }
+    }
+    if len(a.AllowedExternalChains) > 0 && !containsString(a.AllowedExternalChains, msg.ExternalChain) {
+        return authz.AcceptResponse{}, sdkerrors.Wrapf(sdkerrors.ErrUnauthorized, "external chain %s not allowed", msg.ExternalChain)
+    }
+    if a.MaxAmount.Empty() {
+        return authz.AcceptResponse{Accept: true}, nil
+    }
+
+    remaining, hasNeg := a.MaxAmount.SafeSub(msg.Amount...)
+    if hasNeg {
+        return authz.AcceptResponse{}, sdkerrors.Wrapf(sdkerrors.ErrInsufficientFunds, "amount %s exceeds remaining grant %s", msg.Amount, a.MaxAmount)
+    }
+    if remaining.IsZero() {
+        return authz.AcceptResponse{Accept: true, Delete: true}, nil
+    }
+    return authz.AcceptResponse{
+        Accept:  true,
+        Updated: NewHTLCAuthorization(a.MsgType, a.AllowedDenoms, remaining, a.AllowedExternalChains),
+    }, nil
+}
+
+func containsString(list []string, s string) bool {
+    for _, item := range list {
+        if item == s {
+            return true
+        }
+    }
+    return false
+}
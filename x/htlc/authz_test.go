@@ -0,0 +1,115 @@
+// x/htlc/authz_test.go
+package htlc_test
+
+import (
+    "context"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/require"
+
+    codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+    "github.com/cosmos/cosmos-sdk/x/authz"
+
+    "github.com/your_repo/x/htlc"
+)
+
+func TestHTLCAuthorization_PacksIntoGrant(t *testing.T) {
+    registry := codectypes.NewInterfaceRegistry()
+    htlc.RegisterInterfaces(registry)
+
+    auth := htlc.NewHTLCAuthorization(htlc.TypeURLMsgCreateHTLC, []string{"atom"}, sdk.NewCoins(sdk.NewInt64Coin("atom", 100)), []string{"ethereum"})
+    grant, err := authz.NewGrant(time.Unix(1_700_000_000, 0), auth, nil)
+    require.NoError(t, err)
+    require.NoError(t, grant.UnpackInterfaces(registry))
+
+    unpacked, err := grant.GetAuthorization()
+    require.NoError(t, err)
+    require.Equal(t, auth, unpacked)
+}
+
+func TestHTLCAuthorization_AcceptCreate(t *testing.T) {
+    granter := sdk.AccAddress([]byte("granter___________"))
+    create := func(amount sdk.Coins, chain string) *htlc.MsgCreateHTLC {
+        return &htlc.MsgCreateHTLC{
+            Sender:        granter,
+            Receiver:      sdk.AccAddress([]byte("receiver__________")),
+            Amount:        amount,
+            HashLock:      sdk.Sha256([]byte("secret")),
+            TimeLock:      1_700_000_000,
+            ExternalChain: chain,
+        }
+    }
+    atoms := func(n int64) sdk.Coins { return sdk.NewCoins(sdk.NewInt64Coin("atom", n)) }
+
+    cases := []struct {
+        name    string
+        auth    *htlc.HTLCAuthorization
+        msg     *htlc.MsgCreateHTLC
+        err     error
+        delete  bool
+        updated sdk.Coins
+    }{
+        {"no limits", htlc.NewHTLCAuthorization(htlc.TypeURLMsgCreateHTLC, nil, nil, nil), create(atoms(100), ""), nil, false, nil},
+        {"allowed denom", htlc.NewHTLCAuthorization(htlc.TypeURLMsgCreateHTLC, []string{"atom"}, nil, nil), create(atoms(100), ""), nil, false, nil},
+        {"denied denom", htlc.NewHTLCAuthorization(htlc.TypeURLMsgCreateHTLC, []string{"uosmo"}, nil, nil), create(atoms(100), ""), sdkerrors.ErrUnauthorized, false, nil},
+        {"allowed chain", htlc.NewHTLCAuthorization(htlc.TypeURLMsgCreateHTLC, nil, nil, []string{"ethereum"}), create(atoms(100), "ethereum"), nil, false, nil},
+        {"denied chain", htlc.NewHTLCAuthorization(htlc.TypeURLMsgCreateHTLC, nil, nil, []string{"ethereum"}), create(atoms(100), "polygon"), sdkerrors.ErrUnauthorized, false, nil},
+        {"below max amount", htlc.NewHTLCAuthorization(htlc.TypeURLMsgCreateHTLC, nil, atoms(250), nil), create(atoms(100), ""), nil, false, atoms(150)},
+        {"exactly max amount", htlc.NewHTLCAuthorization(htlc.TypeURLMsgCreateHTLC, nil, atoms(100), nil), create(atoms(100), ""), nil, true, nil},
+        {"above max amount", htlc.NewHTLCAuthorization(htlc.TypeURLMsgCreateHTLC, nil, atoms(99), nil), create(atoms(100), ""), sdkerrors.ErrInsufficientFunds, false, nil},
+        {"grant for another msg", htlc.NewHTLCAuthorization(htlc.TypeURLMsgClaimHTLC, nil, nil, nil), create(atoms(100), ""), sdkerrors.ErrInvalidType, false, nil},
+    }
+
+    for _, tc := range cases {
+        t.Run(tc.name, func(t *testing.T) {
+            res, err := tc.auth.Accept(context.Background(), tc.msg)
+            if tc.err != nil {
+                require.ErrorIs(t, err, tc.err)
+                return
+            }
+            require.NoError(t, err)
+            require.True(t, res.Accept)
+            require.Equal(t, tc.delete, res.Delete)
+            if tc.updated == nil {
+                require.Nil(t, res.Updated)
+                return
+            }
+            updated, ok := res.Updated.(*htlc.HTLCAuthorization)
+            require.True(t, ok)
+            require.Equal(t, tc.updated, updated.MaxAmount)
+            require.Equal(t, tc.auth.AllowedDenoms, updated.AllowedDenoms)
+            require.Equal(t, tc.auth.AllowedExternalChains, updated.AllowedExternalChains)
+        })
+    }
+}
+
+func TestHTLCAuthorization_AcceptClaimAndRefund(t *testing.T) {
+    granter := sdk.AccAddress([]byte("granter___________"))
+    grantee := sdk.AccAddress([]byte("grantee___________"))
+    secret := make([]byte, htlc.SecretLength)
+    claims := htlc.NewHTLCAuthorization(htlc.TypeURLMsgClaimHTLC, nil, nil, nil)
+
+    for name, target := range map[string]sdk.AccAddress{"no target": nil, "target is the granter": granter} {
+        msg := htlc.NewMsgClaimHTLC(granter, "id", secret, nil, target)
+        res, err := claims.Accept(context.Background(), &msg)
+        require.NoError(t, err, name)
+        require.True(t, res.Accept, name)
+    }
+
+    // The grantee may settle the claim but not take the coins
+    msg := htlc.NewMsgClaimHTLC(granter, "id", secret, nil, grantee)
+    _, err := claims.Accept(context.Background(), &msg)
+    require.ErrorIs(t, err, sdkerrors.ErrUnauthorized)
+
+    refund := htlc.NewMsgRefundHTLC(granter, "id")
+    res, err := claims.Accept(context.Background(), &refund)
+    require.NoError(t, err)
+    require.False(t, res.Accept)
+
+    res, err = htlc.NewHTLCAuthorization(htlc.TypeURLMsgRefundHTLC, nil, nil, nil).Accept(context.Background(), &refund)
+    require.NoError(t, err)
+    require.True(t, res.Accept)
+}
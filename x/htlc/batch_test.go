@@ -0,0 +1,173 @@
+// x/htlc/batch_test.go
+package htlc_test
+
+import (
+    "fmt"
+    "time"
+
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+
+    "github.com/your_repo/x/htlc"
+)
+
+// batchCreate builds a create for each amount, locking until now+2h under
+// secrets "secret-0", "secret-1", ...
+func (s *KeeperTestSuite) batchCreate(bestEffort bool, amounts ...int64) htlc.MsgBatchCreate {
+    msg := htlc.MsgBatchCreate{Sender: s.sender, BestEffort: bestEffort}
+    for i, amount := range amounts {
+        msg.Creates = append(msg.Creates, htlc.MsgCreateHTLC{
+            Sender:   s.sender,
+            Receiver: s.receiver,
+            Amount:   sdk.NewCoins(sdk.NewInt64Coin("atom", amount)),
+            HashLock: sdk.Sha256([]byte(fmt.Sprintf("secret-%d", i))),
+            TimeLock: uint64(s.ctx.BlockTime().Add(2 * time.Hour).Unix()),
+        })
+    }
+    return msg
+}
+
+// requireResults checks the per-item outcome of a batch
+func (s *KeeperTestSuite) requireResults(results []htlc.BatchItemResult, success ...bool) {
+    s.Require().Len(results, len(success))
+    for i, result := range results {
+        s.Require().Equal(uint32(i), result.Index)
+        s.Require().Equal(success[i], result.Success, "item %d", i)
+        s.Require().Equal(success[i], result.Error == "", "item %d", i)
+    }
+}
+
+func (s *KeeperTestSuite) TestBatchCreate_Atomic() {
+    base := htlc.HTLCID(s.sender, s.ctx.BlockTime())
+
+    results, err := s.keeper.BatchCreate(s.ctx, s.batchCreate(false, 100, 200))
+    s.Require().NoError(err)
+    s.requireResults(results, true, true)
+    for i, result := range results {
+        s.Require().Equal(fmt.Sprintf("%s-%d", base, i), result.ID)
+        _, err := s.keeper.GetHTLC(s.ctx, result.ID)
+        s.Require().NoError(err)
+    }
+    s.Require().Equal(sdk.NewCoins(sdk.NewInt64Coin("atom", 300)), s.bank.ModuleBalance("htlc"))
+
+    // One unfunded item fails the whole batch, and the tx reverts every item
+    s.AdvanceTime(time.Second)
+    txCtx, _ := s.ctx.CacheContext()
+    results, err = s.keeper.BatchCreate(txCtx, s.batchCreate(false, 100, 5000))
+    s.Require().ErrorIs(err, sdkerrors.ErrInsufficientFunds)
+    s.Require().Nil(results)
+    _, err = s.keeper.GetHTLC(s.ctx, htlc.HTLCID(s.sender, s.ctx.BlockTime())+"-0")
+    s.Require().ErrorIs(err, htlc.ErrHTLCNotFound)
+}
+
+func (s *KeeperTestSuite) TestBatchCreate_BestEffort() {
+    results, err := s.keeper.BatchCreate(s.ctx, s.batchCreate(true, 100, 5000, 200))
+    s.Require().NoError(err)
+    s.requireResults(results, true, false, true)
+
+    _, err = s.keeper.GetHTLC(s.ctx, results[1].ID)
+    s.Require().ErrorIs(err, htlc.ErrHTLCNotFound)
+    for _, i := range []int{0, 2} {
+        _, err := s.keeper.GetHTLC(s.ctx, results[i].ID)
+        s.Require().NoError(err)
+    }
+    s.Require().Equal(sdk.NewCoins(sdk.NewInt64Coin("atom", 300)), s.bank.ModuleBalance("htlc"))
+}
+
+func (s *KeeperTestSuite) TestBatchClaim_Atomic() {
+    created, err := s.keeper.BatchCreate(s.ctx, s.batchCreate(false, 100, 200))
+    s.Require().NoError(err)
+    s.AdvanceTime(time.Minute)
+
+    claims := func(secrets ...string) htlc.MsgBatchClaim {
+        msg := htlc.MsgBatchClaim{Claimer: s.receiver}
+        for i, secret := range secrets {
+            msg.Claims = append(msg.Claims, htlc.MsgClaimHTLC{Claimer: s.receiver, ID: created[i].ID, Secret: []byte(secret)})
+        }
+        return msg
+    }
+
+    txCtx, _ := s.ctx.CacheContext()
+    results, err := s.keeper.BatchClaim(txCtx, claims("secret-0", "wrong"))
+    s.Require().ErrorIs(err, htlc.ErrInvalidSecret)
+    s.Require().Nil(results)
+    stored, err := s.keeper.GetHTLC(s.ctx, created[0].ID)
+    s.Require().NoError(err)
+    s.Require().False(stored.Claimed)
+
+    results, err = s.keeper.BatchClaim(s.ctx, claims("secret-0", "secret-1"))
+    s.Require().NoError(err)
+    s.requireResults(results, true, true)
+    s.Require().Equal(sdk.NewCoins(sdk.NewInt64Coin("atom", 300)), s.bank.GetAllBalances(s.ctx, s.receiver))
+}
+
+func (s *KeeperTestSuite) TestBatchClaim_BestEffort() {
+    created, err := s.keeper.BatchCreate(s.ctx, s.batchCreate(false, 100, 200))
+    s.Require().NoError(err)
+    s.AdvanceTime(time.Minute)
+
+    results, err := s.keeper.BatchClaim(s.ctx, htlc.MsgBatchClaim{
+        Claimer:    s.receiver,
+        BestEffort: true,
+        Claims: []htlc.MsgClaimHTLC{
+            {Claimer: s.receiver, ID: created[0].ID, Secret: []byte("wrong")},
+            {Claimer: s.receiver, ID: created[1].ID, Secret: []byte("secret-1")},
+        },
+    })
+    s.Require().NoError(err)
+    s.requireResults(results, false, true)
+    s.Require().Equal(created[0].ID, results[0].ID)
+    s.Require().Equal(sdk.NewCoins(sdk.NewInt64Coin("atom", 200)), s.bank.GetAllBalances(s.ctx, s.receiver))
+}
+
+func (s *KeeperTestSuite) TestBatchRefund_Atomic() {
+    created, err := s.keeper.BatchCreate(s.ctx, s.batchCreate(false, 100, 200))
+    s.Require().NoError(err)
+    late := s.CreateHTLC([]byte("late"), 4*time.Hour)
+    s.AdvanceTime(2 * time.Hour)
+
+    refunds := func(ids ...string) htlc.MsgBatchRefund {
+        msg := htlc.MsgBatchRefund{Sender: s.sender}
+        for _, id := range ids {
+            msg.Refunds = append(msg.Refunds, htlc.MsgRefundHTLC{Sender: s.sender, ID: id})
+        }
+        return msg
+    }
+
+    txCtx, _ := s.ctx.CacheContext()
+    results, err := s.keeper.BatchRefund(txCtx, refunds(created[0].ID, late.ID))
+    s.Require().ErrorIs(err, htlc.ErrNotExpired)
+    s.Require().Nil(results)
+    stored, err := s.keeper.GetHTLC(s.ctx, created[0].ID)
+    s.Require().NoError(err)
+    s.Require().False(stored.Refunded)
+
+    results, err = s.keeper.BatchRefund(s.ctx, refunds(created[0].ID, created[1].ID))
+    s.Require().NoError(err)
+    s.requireResults(results, true, true)
+    s.Require().Equal(late.Amount, s.bank.ModuleBalance("htlc"))
+}
+
+func (s *KeeperTestSuite) TestBatchRefund_BestEffort() {
+    created, err := s.keeper.BatchCreate(s.ctx, s.batchCreate(false, 100, 200))
+    s.Require().NoError(err)
+    late := s.CreateHTLC([]byte("late"), 4*time.Hour)
+    s.AdvanceTime(2 * time.Hour)
+
+    results, err := s.keeper.BatchRefund(s.ctx, htlc.MsgBatchRefund{
+        Sender:     s.sender,
+        BestEffort: true,
+        Refunds: []htlc.MsgRefundHTLC{
+            {Sender: s.sender, ID: created[0].ID},
+            {Sender: s.sender, ID: late.ID},
+            {Sender: s.sender, ID: created[1].ID},
+        },
+    })
+    s.Require().NoError(err)
+    s.requireResults(results, true, false, true)
+
+    stored, err := s.keeper.GetHTLC(s.ctx, late.ID)
+    s.Require().NoError(err)
+    s.Require().False(stored.Refunded)
+    s.Require().Equal(late.Amount, s.bank.ModuleBalance("htlc"))
+}
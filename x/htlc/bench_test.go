@@ -0,0 +1,190 @@
+// x/htlc/bench_test.go
+package htlc_test
+
+import (
+    "encoding/binary"
+    "fmt"
+    "testing"
+    "time"
+
+    "github.com/cosmos/cosmos-sdk/codec"
+    codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+    "github.com/cosmos/cosmos-sdk/store"
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    "github.com/tendermint/tendermint/libs/log"
+    tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+    dbm "github.com/tendermint/tm-db"
+
+    "github.com/your_repo/x/htlc"
+    "github.com/your_repo/x/htlc/testutil"
+)
+
+var (
+    benchSender   = sdk.AccAddress([]byte("sender____________"))
+    benchReceiver = sdk.AccAddress([]byte("receiver__________"))
+    benchAmount   = sdk.NewCoins(sdk.NewInt64Coin("atom", 100))
+)
+
+func setupBenchKeeper(b *testing.B) (sdk.Context, htlc.Keeper, *testutil.MockBankKeeper) {
+    db := dbm.NewMemDB()
+    cms := store.NewCommitMultiStore(db)
+    key := sdk.NewKVStoreKey("htlc")
+    cms.MountStoreWithDB(key, sdk.StoreTypeIAVL, db)
+    if err := cms.LoadLatestVersion(); err != nil {
+        b.Fatal(err)
+    }
+
+    cdc := codec.NewProtoCodec(codectypes.NewInterfaceRegistry())
+    bank := testutil.NewMockBankKeeper()
+    bank.FundAccount(benchSender, sdk.NewCoins(sdk.NewInt64Coin("atom", 1<<62)))
+    k := htlc.NewKeeper(cdc, key, bank, nil, "")
+    ctx := sdk.NewContext(cms, tmproto.Header{Height: 1, Time: genesisTime}, false, log.NewNopLogger())
+    return ctx, k, bank
+}
+
+func benchSecret(i int) []byte {
+    secret := make([]byte, htlc.SecretLength)
+    binary.BigEndian.PutUint64(secret, uint64(i))
+    return secret
+}
+
+// benchCreate creates an HTLC for secret in a new block one second after the
+// previous one, and returns the block context and the HTLC ID
+func benchCreate(b *testing.B, ctx sdk.Context, k htlc.Keeper, secret []byte) (sdk.Context, string) {
+    ctx = ctx.WithBlockTime(ctx.BlockTime().Add(time.Second))
+    msg := htlc.MsgCreateHTLC{
+        Sender:   benchSender,
+        Receiver: benchReceiver,
+        Amount:   benchAmount,
+        HashLock: sdk.Sha256(secret),
+        TimeLock: uint64(ctx.BlockTime().Add(2 * time.Hour).Unix()),
+    }
+    id, err := k.CreateHTLC(ctx, msg)
+    if err != nil {
+        b.Fatal(err)
+    }
+    return ctx, id
+}
+
+func reportGas(b *testing.B, gas uint64) {
+    b.ReportMetric(float64(gas)/float64(b.N), "gas/op")
+}
+
+func BenchmarkCreateHTLC(b *testing.B) {
+    ctx, k, _ := setupBenchKeeper(b)
+    var gas uint64
+
+    b.ResetTimer()
+    for i := 0; i < b.N; i++ {
+        ctx = ctx.WithGasMeter(sdk.NewInfiniteGasMeter())
+        ctx, _ = benchCreate(b, ctx, k, benchSecret(i))
+        gas += ctx.GasMeter().GasConsumed()
+    }
+    reportGas(b, gas)
+}
+
+func BenchmarkClaimHTLC(b *testing.B) {
+    ctx, k, _ := setupBenchKeeper(b)
+    var gas uint64
+
+    b.ResetTimer()
+    for i := 0; i < b.N; i++ {
+        b.StopTimer()
+        secret := benchSecret(i)
+        var id string
+        ctx, id = benchCreate(b, ctx, k, secret)
+        ctx = ctx.WithGasMeter(sdk.NewInfiniteGasMeter())
+        b.StartTimer()
+
+        if err := k.ClaimHTLC(ctx, htlc.MsgClaimHTLC{Claimer: benchReceiver, ID: id, Secret: secret}); err != nil {
+            b.Fatal(err)
+        }
+        gas += ctx.GasMeter().GasConsumed()
+    }
+    reportGas(b, gas)
+}
+
+// BenchmarkClaimHTLC_PartialFill claims partial fills against trees of
+// growing depth. Fills go through the leaves of one HTLC in order, and a new
+// HTLC over the same tree is locked by the sender once all were used, so the
+// module only ever holds what the HTLCs locked.
+func BenchmarkClaimHTLC_PartialFill(b *testing.B) {
+    for _, depth := range []int{1, 4, 8, 12, 16} {
+        tree := testutil.BuildMerkleTree(testutil.NewMerkleSecrets(1 << depth))
+        amount := sdk.NewCoins(sdk.NewInt64Coin("atom", 100*int64(len(tree.Secrets))))
+
+        b.Run(fmt.Sprintf("depth=%d", depth), func(b *testing.B) {
+            ctx, k, _ := setupBenchKeeper(b)
+            var gas uint64
+            var id string
+
+            b.ResetTimer()
+            for i := 0; i < b.N; i++ {
+                leaf := i % len(tree.Secrets)
+                if leaf == 0 {
+                    b.StopTimer()
+                    ctx = ctx.WithBlockTime(ctx.BlockTime().Add(time.Second))
+                    msg := htlc.MsgCreateHTLC{
+                        Sender:   benchSender,
+                        Receiver: benchReceiver,
+                        Amount:   amount,
+                        HashLock: tree.Root,
+                        TimeLock: uint64(ctx.BlockTime().Add(2 * time.Hour).Unix()),
+                        Parts:    uint64(len(tree.Secrets)),
+                    }
+                    var err error
+                    if id, err = k.CreateHTLC(ctx, msg); err != nil {
+                        b.Fatal(err)
+                    }
+                    b.StartTimer()
+                }
+                ctx = ctx.WithGasMeter(sdk.NewInfiniteGasMeter())
+
+                msg := htlc.MsgClaimHTLC{Claimer: benchReceiver, ID: id, Secret: tree.Secrets[leaf], MerkleProof: tree.Proofs[leaf]}
+                if err := k.ClaimHTLC(ctx, msg); err != nil {
+                    b.Fatal(err)
+                }
+                gas += ctx.GasMeter().GasConsumed()
+            }
+            reportGas(b, gas)
+        })
+    }
+}
+
+func BenchmarkRefundHTLC(b *testing.B) {
+    ctx, k, _ := setupBenchKeeper(b)
+    var gas uint64
+
+    b.ResetTimer()
+    for i := 0; i < b.N; i++ {
+        b.StopTimer()
+        var id string
+        ctx, id = benchCreate(b, ctx, k, benchSecret(i))
+        created := ctx.BlockTime()
+        refundCtx := ctx.WithBlockTime(created.Add(3 * time.Hour)).WithGasMeter(sdk.NewInfiniteGasMeter())
+        b.StartTimer()
+
+        if err := k.RefundHTLC(refundCtx, htlc.MsgRefundHTLC{Sender: benchSender, ID: id}); err != nil {
+            b.Fatal(err)
+        }
+        gas += refundCtx.GasMeter().GasConsumed()
+    }
+    reportGas(b, gas)
+}
+
+func BenchmarkVerifyMerkleProof(b *testing.B) {
+    for _, depth := range []int{1, 8, 16, htlc.MaxMerkleProofDepth} {
+        proof := make([][]byte, depth)
+        for i := range proof {
+            proof[i] = sdk.Sha256(benchSecret(i))
+        }
+        leaf := sdk.Sha256([]byte("leaf"))
+        root := sdk.Sha256([]byte("root"))
+
+        b.Run(fmt.Sprintf("depth=%d", depth), func(b *testing.B) {
+            for i := 0; i < b.N; i++ {
+                htlc.VerifyMerkleProof(leaf, proof, root)
+            }
+        })
+    }
+}
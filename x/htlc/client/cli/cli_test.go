@@ -0,0 +1,184 @@
+// x/htlc/client/cli/cli_test.go
+package cli_test
+
+import (
+    "bytes"
+    "encoding/hex"
+    "fmt"
+    "testing"
+    "time"
+
+    "github.com/spf13/cobra"
+    "github.com/stretchr/testify/suite"
+
+    "github.com/cosmos/cosmos-sdk/client/flags"
+    clitestutil "github.com/cosmos/cosmos-sdk/testutil/cli"
+    "github.com/cosmos/cosmos-sdk/testutil/network"
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    authcli "github.com/cosmos/cosmos-sdk/x/auth/client/cli"
+    bankcli "github.com/cosmos/cosmos-sdk/x/bank/client/cli"
+    banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+
+    "github.com/your_repo/x/htlc"
+    "github.com/your_repo/x/htlc/client/cli"
+    htlctestutil "github.com/your_repo/x/htlc/testutil"
+)
+
+type IntegrationTestSuite struct {
+    suite.Suite
+
+    cfg     network.Config
+    network *network.Network
+}
+
+func TestIntegrationTestSuite(t *testing.T) {
+    suite.Run(t, new(IntegrationTestSuite))
+}
+
+func (s *IntegrationTestSuite) SetupSuite() {
+    s.T().Log("setting up integration test suite")
+
+    cfg, err := network.DefaultConfigWithAppConfig(htlctestutil.AppConfig)
+    s.Require().NoError(err)
+    cfg.NumValidators = 2
+    s.cfg = cfg
+
+    s.network, err = network.New(s.T(), s.T().TempDir(), cfg)
+    s.Require().NoError(err)
+    s.Require().NoError(s.network.WaitForNextBlock())
+}
+
+func (s *IntegrationTestSuite) TearDownSuite() {
+    s.T().Log("tearing down integration test suite")
+    s.network.Cleanup()
+}
+
+func (s *IntegrationTestSuite) txFlags(from sdk.AccAddress) []string {
+    return []string{
+        fmt.Sprintf("--%s=%s", flags.FlagFrom, from),
+        fmt.Sprintf("--%s=true", flags.FlagSkipConfirmation),
+        fmt.Sprintf("--%s=%s", flags.FlagBroadcastMode, flags.BroadcastSync),
+        fmt.Sprintf("--%s=%s", flags.FlagFees, sdk.NewCoins(sdk.NewInt64Coin(s.cfg.BondDenom, 10))),
+        fmt.Sprintf("--%s=json", flags.FlagOutput),
+    }
+}
+
+// execTx runs a tx command, waits for it to be committed and returns the
+// committed response, events included
+func (s *IntegrationTestSuite) execTx(cmd func() *cobra.Command, args []string, from sdk.AccAddress) sdk.TxResponse {
+    val := s.network.Validators[0]
+    out, err := clitestutil.ExecTestCLICmd(val.ClientCtx, cmd(), append(args, s.txFlags(from)...))
+    s.Require().NoError(err)
+
+    var broadcast sdk.TxResponse
+    s.Require().NoError(val.ClientCtx.Codec.UnmarshalJSON(out.Bytes(), &broadcast))
+    s.Require().Equal(uint32(0), broadcast.Code, broadcast.RawLog)
+    s.Require().NoError(s.network.WaitForNextBlock())
+
+    out, err = clitestutil.ExecTestCLICmd(val.ClientCtx, authcli.QueryTxCmd(), []string{broadcast.TxHash, fmt.Sprintf("--%s=json", flags.FlagOutput)})
+    s.Require().NoError(err)
+    var committed sdk.TxResponse
+    s.Require().NoError(val.ClientCtx.Codec.UnmarshalJSON(out.Bytes(), &committed))
+    return committed
+}
+
+func (s *IntegrationTestSuite) balance(addr sdk.AccAddress, denom string) sdk.Int {
+    val := s.network.Validators[0]
+    out, err := clitestutil.ExecTestCLICmd(val.ClientCtx, bankcli.GetBalancesCmd(), []string{addr.String(), fmt.Sprintf("--%s=json", flags.FlagOutput)})
+    s.Require().NoError(err)
+    var res banktypes.QueryAllBalancesResponse
+    s.Require().NoError(val.ClientCtx.Codec.UnmarshalJSON(out.Bytes(), &res))
+    return res.Balances.AmountOf(denom)
+}
+
+// createHTLC locks amount from sender to receiver and returns the created
+// HTLC. It expires well past the public withdrawal period, so the HTLC stays
+// open for the whole test.
+func (s *IntegrationTestSuite) createHTLC(sender, receiver sdk.AccAddress, amount sdk.Coin, secret []byte) htlc.HTLC {
+    hashLock := sdk.Sha256(secret)
+    timeLock := time.Now().Add(3 * time.Hour).Unix()
+    s.execTx(cli.NewCreateHTLCCmd, []string{
+        receiver.String(), amount.String(), hex.EncodeToString(hashLock), fmt.Sprint(timeLock),
+        fmt.Sprintf("--%s=ethereum", cli.FlagExternalChain),
+    }, sender)
+
+    val := s.network.Validators[0]
+    out, err := clitestutil.ExecTestCLICmd(val.ClientCtx, cli.NewQueryByHashLockCmd(), []string{hex.EncodeToString(hashLock), fmt.Sprintf("--%s=json", flags.FlagOutput)})
+    s.Require().NoError(err)
+    var res htlc.QueryHTLCsResponse
+    s.Require().NoError(val.ClientCtx.Codec.UnmarshalJSON(out.Bytes(), &res))
+    s.Require().Len(res.HTLCs, 1)
+    return res.HTLCs[0]
+}
+
+func (s *IntegrationTestSuite) TestCreateAndClaim() {
+    sender := s.network.Validators[0].Address
+    receiver := s.network.Validators[1].Address
+    denom := fmt.Sprintf("%stoken", s.network.Validators[0].Moniker)
+    amount := sdk.NewInt64Coin(denom, 100)
+    secret := bytes.Repeat([]byte{0xaa}, htlc.SecretLength)
+
+    senderBefore := s.balance(sender, denom)
+    receiverBefore := s.balance(receiver, denom)
+
+    created := s.createHTLC(sender, receiver, amount, secret)
+    s.Require().Equal(senderBefore.Sub(amount.Amount), s.balance(sender, denom))
+
+    res := s.execTx(cli.NewClaimHTLCCmd, []string{created.ID, hex.EncodeToString(secret)}, receiver)
+    s.Require().True(hasEvent(res, htlc.EventTypeStatusChanged, htlc.AttributeKeyStatus, htlc.StatusClaimed.String()))
+    s.Require().Equal(receiverBefore.Add(amount.Amount), s.balance(receiver, denom))
+
+    val := s.network.Validators[0]
+    out, err := clitestutil.ExecTestCLICmd(val.ClientCtx, cli.NewQueryRevealedSecretCmd(), []string{hex.EncodeToString(sdk.Sha256(secret)), fmt.Sprintf("--%s=json", flags.FlagOutput)})
+    s.Require().NoError(err)
+    var revealed htlc.QueryRevealedSecretResponse
+    s.Require().NoError(val.ClientCtx.Codec.UnmarshalJSON(out.Bytes(), &revealed))
+    s.Require().Equal(secret, revealed.Revealed.Secret)
+    s.Require().Equal(created.ID, revealed.Revealed.HTLCID)
+}
+
+func (s *IntegrationTestSuite) TestRefundBeforeExpiry() {
+    sender := s.network.Validators[0].Address
+    receiver := s.network.Validators[1].Address
+    denom := fmt.Sprintf("%stoken", s.network.Validators[0].Moniker)
+    secret := bytes.Repeat([]byte{0xbb}, htlc.SecretLength)
+
+    created := s.createHTLC(sender, receiver, sdk.NewInt64Coin(denom, 50), secret)
+    senderLocked := s.balance(sender, denom)
+
+    // The tx is accepted into a block but fails with the typed error code
+    val := s.network.Validators[0]
+    out, err := clitestutil.ExecTestCLICmd(val.ClientCtx, cli.NewRefundHTLCCmd(), append([]string{created.ID}, s.txFlags(sender)...))
+    s.Require().NoError(err)
+    var broadcast sdk.TxResponse
+    s.Require().NoError(val.ClientCtx.Codec.UnmarshalJSON(out.Bytes(), &broadcast))
+    s.Require().NoError(s.network.WaitForNextBlock())
+
+    out, err = clitestutil.ExecTestCLICmd(val.ClientCtx, authcli.QueryTxCmd(), []string{broadcast.TxHash, fmt.Sprintf("--%s=json", flags.FlagOutput)})
+    s.Require().NoError(err)
+    var committed sdk.TxResponse
+    s.Require().NoError(val.ClientCtx.Codec.UnmarshalJSON(out.Bytes(), &committed))
+    s.Require().Equal(htlc.Codespace, committed.Codespace)
+    s.Require().Equal(htlc.ErrNotExpired.ABCICode(), committed.Code)
+    s.Require().Equal(senderLocked, s.balance(sender, denom))
+
+    out, err = clitestutil.ExecTestCLICmd(val.ClientCtx, cli.NewQueryStatusCmd(), []string{created.ID, fmt.Sprintf("--%s=json", flags.FlagOutput)})
+    s.Require().NoError(err)
+    var status htlc.QueryStatusResponse
+    s.Require().NoError(val.ClientCtx.Codec.UnmarshalJSON(out.Bytes(), &status))
+    s.Require().Equal(htlc.StatusOpen.String(), status.Status)
+}
+
+func hasEvent(res sdk.TxResponse, eventType, key, value string) bool {
+    for _, event := range res.Events {
+        if event.Type != eventType {
+            continue
+        }
+        for _, attr := range event.Attributes {
+            if attr.Key == key && attr.Value == value {
+                return true
+            }
+        }
+    }
+    return false
+}
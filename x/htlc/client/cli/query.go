@@ -0,0 +1,114 @@
+// x/htlc/client/cli/query.go
+package cli
+
+import (
+    "encoding/hex"
+
+    "github.com/cosmos/gogoproto/proto"
+    "github.com/spf13/cobra"
+
+    "github.com/cosmos/cosmos-sdk/client"
+    "github.com/cosmos/cosmos-sdk/client/flags"
+
+    "github.com/your_repo/x/htlc"
+)
+
+// GetQueryCmd returns the query commands of the htlc module
+func GetQueryCmd() *cobra.Command {
+    cmd := &cobra.Command{
+        Use:                        "htlc",
+        Short:                      "Querying commands for the htlc module",
+        DisableFlagParsing:         true,
+        SuggestionsMinimumDistance: 2,
+        RunE:                       client.ValidateCmd,
+    }
+
+    cmd.AddCommand(
+        NewQueryHTLCCmd(),
+        NewQueryStatusCmd(),
+        NewQueryByHashLockCmd(),
+        NewQueryRevealedSecretCmd(),
+        NewQueryRouteCmd(),
+    )
+    return cmd
+}
+
+func NewQueryHTLCCmd() *cobra.Command {
+    return newQueryByIDCmd("htlc [id]", "Query an HTLC by ID", func(cmd *cobra.Command, queryClient htlc.QueryClient, id string) (proto.Message, error) {
+        return queryClient.HTLC(cmd.Context(), &htlc.QueryHTLCRequest{ID: id})
+    })
+}
+
+func NewQueryStatusCmd() *cobra.Command {
+    return newQueryByIDCmd("status [id]", "Query the status of an HTLC at the latest height", func(cmd *cobra.Command, queryClient htlc.QueryClient, id string) (proto.Message, error) {
+        return queryClient.Status(cmd.Context(), &htlc.QueryStatusRequest{ID: id})
+    })
+}
+
+func NewQueryByHashLockCmd() *cobra.Command {
+    return newQueryByHashLockCmd("by-hashlock [hashlock]", "Query the HTLCs locked with a hex hashlock", func(cmd *cobra.Command, queryClient htlc.QueryClient, req *htlc.QueryByHashLockRequest) (proto.Message, error) {
+        return queryClient.HTLCsByHashLock(cmd.Context(), req)
+    })
+}
+
+func NewQueryRevealedSecretCmd() *cobra.Command {
+    return newQueryByHashLockCmd("revealed-secret [hashlock]", "Query the secret revealed for a hex hashlock", func(cmd *cobra.Command, queryClient htlc.QueryClient, req *htlc.QueryByHashLockRequest) (proto.Message, error) {
+        return queryClient.RevealedSecret(cmd.Context(), req)
+    })
+}
+
+func NewQueryRouteCmd() *cobra.Command {
+    return newQueryByIDCmd("route [id]", "Query the status of a route and each of its hops", func(cmd *cobra.Command, queryClient htlc.QueryClient, id string) (proto.Message, error) {
+        return queryClient.Route(cmd.Context(), &htlc.QueryRouteRequest{ID: id})
+    })
+}
+
+func newQueryByIDCmd(use, short, query func(*cobra.Command, htlc.QueryClient, string) (proto.Message, error)) *cobra.Command {
+    cmd := &cobra.Command{
+        Use:   use,
+        Short: short,
+        Args:  cobra.ExactArgs(1),
+        RunE: func(cmd *cobra.Command, args []string) error {
+            clientCtx, err := client.GetClientQueryContext(cmd)
+            if err != nil {
+                return err
+            }
+
+            res, err := query(cmd, htlc.NewQueryClient(clientCtx), args[0])
+            if err != nil {
+                return err
+            }
+            return clientCtx.PrintProto(res)
+        },
+    }
+
+    flags.AddQueryFlagsToCmd(cmd)
+    return cmd
+}
+
+func newQueryByHashLockCmd(use, short, query func(*cobra.Command, htlc.QueryClient, *htlc.QueryByHashLockRequest) (proto.Message, error)) *cobra.Command {
+    cmd := &cobra.Command{
+        Use:   use,
+        Short: short,
+        Args:  cobra.ExactArgs(1),
+        RunE: func(cmd *cobra.Command, args []string) error {
+            clientCtx, err := client.GetClientQueryContext(cmd)
+            if err != nil {
+                return err
+            }
+            hashLock, err := hex.DecodeString(args[0])
+            if err != nil {
+                return err
+            }
+
+            res, err := query(cmd, htlc.NewQueryClient(clientCtx), &htlc.QueryByHashLockRequest{HashLock: hashLock})
+            if err != nil {
+                return err
+            }
+            return clientCtx.PrintProto(res)
+        },
+    }
+
+    flags.AddQueryFlagsToCmd(cmd)
+    return cmd
+}
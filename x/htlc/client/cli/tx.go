@@ -0,0 +1,181 @@
+// x/htlc/client/cli/tx.go
+package cli
+
+import (
+    "encoding/hex"
+    "encoding/json"
+    "os"
+    "strconv"
+
+    "github.com/spf13/cobra"
+
+    "github.com/cosmos/cosmos-sdk/client"
+    "github.com/cosmos/cosmos-sdk/client/flags"
+    "github.com/cosmos/cosmos-sdk/client/tx"
+    sdk "github.com/cosmos/cosmos-sdk/types"
+
+    "github.com/your_repo/x/htlc"
+)
+
+const (
+    FlagExternalChain = "external-chain"
+    FlagExternalID    = "external-id"
+    FlagTarget        = "target"
+    FlagParts         = "parts"
+)
+
+// GetTxCmd returns the transaction commands of the htlc module
+func GetTxCmd() *cobra.Command {
+    cmd := &cobra.Command{
+        Use:                        "htlc",
+        Short:                      "HTLC transaction subcommands",
+        DisableFlagParsing:         true,
+        SuggestionsMinimumDistance: 2,
+        RunE:                       client.ValidateCmd,
+    }
+
+    cmd.AddCommand(
+        NewCreateHTLCCmd(),
+        NewClaimHTLCCmd(),
+        NewRefundHTLCCmd(),
+        NewCreateRouteCmd(),
+    )
+    return cmd
+}
+
+func NewCreateHTLCCmd() *cobra.Command {
+    cmd := &cobra.Command{
+        Use:   "create-htlc [receiver] [amount] [hashlock] [timelock]",
+        Short: "Lock coins for receiver under a hex sha256 hashlock until timelock (unix seconds)",
+        Args:  cobra.ExactArgs(4),
+        RunE: func(cmd *cobra.Command, args []string) error {
+            clientCtx, err := client.GetClientTxContext(cmd)
+            if err != nil {
+                return err
+            }
+
+            receiver, err := sdk.AccAddressFromBech32(args[0])
+            if err != nil {
+                return err
+            }
+            amount, err := sdk.ParseCoinsNormalized(args[1])
+            if err != nil {
+                return err
+            }
+            hashLock, err := hex.DecodeString(args[2])
+            if err != nil {
+                return err
+            }
+            timeLock, err := strconv.ParseUint(args[3], 10, 64)
+            if err != nil {
+                return err
+            }
+            externalChain, _ := cmd.Flags().GetString(FlagExternalChain)
+            externalID, _ := cmd.Flags().GetString(FlagExternalID)
+
+            msg := htlc.NewMsgCreateHTLC(clientCtx.GetFromAddress(), receiver, amount, hashLock, timeLock, externalChain, externalID)
+            msg.Parts, _ = cmd.Flags().GetUint64(FlagParts)
+            return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), &msg)
+        },
+    }
+
+    cmd.Flags().String(FlagExternalChain, "", "Counterpart chain of the swap, e.g. ethereum")
+    cmd.Flags().String(FlagExternalID, "", "ID of the counterpart HTLC or escrow on the external chain")
+    cmd.Flags().Uint64(FlagParts, 0, "Split amount into this many partial fills, hashlock is then the Merkle root of the secrets")
+    flags.AddTxFlagsToCmd(cmd)
+    return cmd
+}
+
+func NewClaimHTLCCmd() *cobra.Command {
+    cmd := &cobra.Command{
+        Use:   "claim-htlc [id] [secret]",
+        Short: "Claim an HTLC with its hex-encoded secret",
+        Args:  cobra.ExactArgs(2),
+        RunE: func(cmd *cobra.Command, args []string) error {
+            clientCtx, err := client.GetClientTxContext(cmd)
+            if err != nil {
+                return err
+            }
+
+            secret, err := hex.DecodeString(args[1])
+            if err != nil {
+                return err
+            }
+            var target sdk.AccAddress
+            if targetStr, _ := cmd.Flags().GetString(FlagTarget); targetStr != "" {
+                if target, err = sdk.AccAddressFromBech32(targetStr); err != nil {
+                    return err
+                }
+            }
+
+            msg := htlc.NewMsgClaimHTLC(clientCtx.GetFromAddress(), args[0], secret, nil, target)
+            return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), &msg)
+        },
+    }
+
+    cmd.Flags().String(FlagTarget, "", "Send the claimed coins to this address instead of the receiver")
+    flags.AddTxFlagsToCmd(cmd)
+    return cmd
+}
+
+func NewRefundHTLCCmd() *cobra.Command {
+    cmd := &cobra.Command{
+        Use:   "refund-htlc [id]",
+        Short: "Refund an expired HTLC to its sender",
+        Args:  cobra.ExactArgs(1),
+        RunE: func(cmd *cobra.Command, args []string) error {
+            clientCtx, err := client.GetClientTxContext(cmd)
+            if err != nil {
+                return err
+            }
+
+            msg := htlc.NewMsgRefundHTLC(clientCtx.GetFromAddress(), args[0])
+            return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), &msg)
+        },
+    }
+
+    flags.AddTxFlagsToCmd(cmd)
+    return cmd
+}
+
+func NewCreateRouteCmd() *cobra.Command {
+    cmd := &cobra.Command{
+        Use:   "create-route [hashlock] [hops-file]",
+        Short: "Create the local hop of a multi-hop route under a hex sha256 hashlock",
+        Long: `Create the local hop of a multi-hop route. hops-file is a JSON array of hops in
+payment order, with timelocks decreasing by at least 2h from one hop to the next.
+The local hop has no chain, e.g.:
+
+[
+  {"chain": "ethereum", "sender": "0x...", "receiver": "0x...", "amount": "1000000000000000000", "timelock": 1700090000},
+  {"receiver": "cosmos1...", "amount": "100uatom", "timelock": 1700080000},
+  {"chain": "osmosis", "sender": "osmo1...", "receiver": "osmo1...", "amount": "50uosmo", "timelock": 1700070000}
+]`,
+        Args: cobra.ExactArgs(2),
+        RunE: func(cmd *cobra.Command, args []string) error {
+            clientCtx, err := client.GetClientTxContext(cmd)
+            if err != nil {
+                return err
+            }
+
+            hashLock, err := hex.DecodeString(args[0])
+            if err != nil {
+                return err
+            }
+            bz, err := os.ReadFile(args[1])
+            if err != nil {
+                return err
+            }
+            var hops []htlc.RouteHop
+            if err := json.Unmarshal(bz, &hops); err != nil {
+                return err
+            }
+
+            msg := htlc.NewMsgCreateRoute(clientCtx.GetFromAddress(), hashLock, hops)
+            return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), &msg)
+        },
+    }
+
+    flags.AddTxFlagsToCmd(cmd)
+    return cmd
+}
@@ -0,0 +1,339 @@
+// x/htlc/client/client.go
+package client
+
+import (
+    "context"
+    "fmt"
+    "sync"
+    "time"
+
+    gogogrpc "github.com/cosmos/gogoproto/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+
+    sdkclient "github.com/cosmos/cosmos-sdk/client"
+    "github.com/cosmos/cosmos-sdk/client/tx"
+    "github.com/cosmos/cosmos-sdk/crypto/keyring"
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+    "github.com/cosmos/cosmos-sdk/types/tx/signing"
+
+    "github.com/your_repo/x/htlc"
+)
+
+// Chain is the node connection used by HTLCClient. GRPCChain talks to a real
+// node, testutil.MockChain runs the module in memory for offline tests.
+type Chain interface {
+    // ClientConn serves the htlc Query service
+    gogogrpc.ClientConn
+    // Account returns the account number and next sequence of addr
+    Account(ctx context.Context, addr sdk.AccAddress) (accountNumber, sequence uint64, err error)
+    // BroadcastTx submits a signed tx and returns its CheckTx result
+    BroadcastTx(ctx context.Context, txBytes []byte) (*sdk.TxResponse, error)
+    // GetTx returns the committed tx with the given hash, or nil if it is
+    // not in a block yet
+    GetTx(ctx context.Context, hash string) (*sdk.TxResponse, error)
+}
+
+// Config holds the chain and fee settings used to sign transactions
+type Config struct {
+    ChainID  string
+    GasLimit uint64
+    Fees     sdk.Coins
+    // PollInterval is how often tx inclusion and revealed secrets are polled
+    PollInterval time.Duration
+}
+
+// DefaultConfig returns a Config for chainID with a gas limit fitting any
+// single htlc message
+func DefaultConfig(chainID string) Config {
+    return Config{
+        ChainID:      chainID,
+        GasLimit:     300_000,
+        PollInterval: time.Second,
+    }
+}
+
+// HTLCClient builds, signs and broadcasts htlc transactions with keys from a
+// keyring, and queries HTLCs through the module's Query service
+type HTLCClient struct {
+    chain    Chain
+    query    htlc.QueryClient
+    txConfig sdkclient.TxConfig
+    keyring  keyring.Keyring
+    cfg      Config
+
+    // mu serializes signing and guards sequences
+    mu sync.Mutex
+    // sequences holds the next sequence of each signer that has txs accepted
+    // by the node but maybe not committed yet, whose committed account
+    // sequence is therefore stale
+    sequences map[string]uint64
+}
+
+func NewHTLCClient(chain Chain, txConfig sdkclient.TxConfig, kr keyring.Keyring, cfg Config) *HTLCClient {
+    return &HTLCClient{
+        chain:     chain,
+        query:     htlc.NewQueryClient(chain),
+        txConfig:  txConfig,
+        keyring:   kr,
+        cfg:       cfg,
+        sequences: make(map[string]uint64),
+    }
+}
+
+// Create locks msg.Amount for msg.Receiver, signing with the key named from,
+// and returns the ID of the new HTLC. msg.Sender is set from the key.
+func (c *HTLCClient) Create(ctx context.Context, from string, msg htlc.MsgCreateHTLC) (string, error) {
+    sender, err := c.address(from)
+    if err != nil {
+        return "", err
+    }
+    msg.Sender = sender
+    if err := msg.ValidateBasic(); err != nil {
+        return "", err
+    }
+
+    res, err := c.signAndBroadcast(ctx, from, &msg)
+    if err != nil {
+        return "", err
+    }
+    id, ok := findAttribute(res, htlc.EventTypeStatusChanged, htlc.AttributeKeyID)
+    if !ok {
+        return "", fmt.Errorf("tx %s has no %s event", res.TxHash, htlc.EventTypeStatusChanged)
+    }
+    return id, nil
+}
+
+// Claim reveals secret to release HTLC id, signing with the key named from.
+// proof is only needed for partial-fill HTLCs.
+func (c *HTLCClient) Claim(ctx context.Context, from, id string, secret []byte, proof [][]byte) (*sdk.TxResponse, error) {
+    claimer, err := c.address(from)
+    if err != nil {
+        return nil, err
+    }
+    msg := htlc.NewMsgClaimHTLC(claimer, id, secret, proof, nil)
+    if err := msg.ValidateBasic(); err != nil {
+        return nil, err
+    }
+    return c.signAndBroadcast(ctx, from, &msg)
+}
+
+// Refund returns the coins of expired HTLC id to its sender
+func (c *HTLCClient) Refund(ctx context.Context, from, id string) (*sdk.TxResponse, error) {
+    sender, err := c.address(from)
+    if err != nil {
+        return nil, err
+    }
+    msg := htlc.NewMsgRefundHTLC(sender, id)
+    if err := msg.ValidateBasic(); err != nil {
+        return nil, err
+    }
+    return c.signAndBroadcast(ctx, from, &msg)
+}
+
+// Get returns the HTLC with the given ID
+func (c *HTLCClient) Get(ctx context.Context, id string) (htlc.HTLC, error) {
+    res, err := c.query.HTLC(ctx, &htlc.QueryHTLCRequest{ID: id})
+    if err != nil {
+        return htlc.HTLC{}, err
+    }
+    return res.HTLC, nil
+}
+
+// CreateRoute locks the local hop of a multi-hop route, signing with the key
+// named from, and returns the route ID. The other hops are left to their
+// senders, see the htlc_route_hop events of the tx.
+func (c *HTLCClient) CreateRoute(ctx context.Context, from string, hashLock []byte, hops []htlc.RouteHop) (string, error) {
+    sender, err := c.address(from)
+    if err != nil {
+        return "", err
+    }
+    msg := htlc.NewMsgCreateRoute(sender, hashLock, hops)
+    if err := msg.ValidateBasic(); err != nil {
+        return "", err
+    }
+
+    res, err := c.signAndBroadcast(ctx, from, &msg)
+    if err != nil {
+        return "", err
+    }
+    id, ok := findAttribute(res, htlc.EventTypeStatusChanged, htlc.AttributeKeyID)
+    if !ok {
+        return "", fmt.Errorf("tx %s has no %s event", res.TxHash, htlc.EventTypeStatusChanged)
+    }
+    return id, nil
+}
+
+// Route returns the status of the route with the given ID and of its hops
+func (c *HTLCClient) Route(ctx context.Context, id string) (htlc.QueryRouteResponse, error) {
+    res, err := c.query.Route(ctx, &htlc.QueryRouteRequest{ID: id})
+    if err != nil {
+        return htlc.QueryRouteResponse{}, err
+    }
+    return *res, nil
+}
+
+// ListFilter selects the HTLCs returned by List. Exactly one field must be set.
+type ListFilter struct {
+    Sender   sdk.AccAddress
+    Receiver sdk.AccAddress
+    HashLock []byte
+}
+
+// List returns the HTLCs matching filter
+func (c *HTLCClient) List(ctx context.Context, filter ListFilter) ([]htlc.HTLC, error) {
+    var (
+        res *htlc.QueryHTLCsResponse
+        err error
+    )
+    switch {
+    case !filter.Sender.Empty() && filter.Receiver.Empty() && len(filter.HashLock) == 0:
+        res, err = c.query.HTLCsBySender(ctx, &htlc.QueryHTLCsByAddressRequest{Address: filter.Sender.String()})
+    case filter.Sender.Empty() && !filter.Receiver.Empty() && len(filter.HashLock) == 0:
+        res, err = c.query.HTLCsByReceiver(ctx, &htlc.QueryHTLCsByAddressRequest{Address: filter.Receiver.String()})
+    case filter.Sender.Empty() && filter.Receiver.Empty() && len(filter.HashLock) > 0:
+        res, err = c.query.HTLCsByHashLock(ctx, &htlc.QueryByHashLockRequest{HashLock: filter.HashLock})
+    default:
+        return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "list filter must set exactly one of sender, receiver or hashlock")
+    }
+    if err != nil {
+        return nil, err
+    }
+    return res.HTLCs, nil
+}
+
+// WaitForClaim blocks until the secret for hashLock is revealed on chain by
+// a claim, or until ctx is done
+func (c *HTLCClient) WaitForClaim(ctx context.Context, hashLock []byte) (htlc.RevealedSecret, error) {
+    ticker := time.NewTicker(c.cfg.PollInterval)
+    defer ticker.Stop()
+
+    for {
+        res, err := c.query.RevealedSecret(ctx, &htlc.QueryByHashLockRequest{HashLock: hashLock})
+        if err == nil {
+            return res.Revealed, nil
+        }
+        // The Query service reports unrevealed secrets as NotFound
+        if status.Code(err) != codes.NotFound {
+            return htlc.RevealedSecret{}, err
+        }
+
+        select {
+        case <-ctx.Done():
+            return htlc.RevealedSecret{}, ctx.Err()
+        case <-ticker.C:
+        }
+    }
+}
+
+func (c *HTLCClient) address(from string) (sdk.AccAddress, error) {
+    record, err := c.keyring.Key(from)
+    if err != nil {
+        return nil, err
+    }
+    return record.GetAddress()
+}
+
+// signAndBroadcast signs msg with the key named from, broadcasts it and waits
+// until it is committed. A tx failing in CheckTx or DeliverTx is returned as
+// the registered error of its codespace and code, so callers can use
+// errors.Is against the htlc errors. Sequences are tracked locally, so
+// concurrent calls for one key sign consecutive sequences instead of all
+// reading the committed one.
+func (c *HTLCClient) signAndBroadcast(ctx context.Context, from string, msg sdk.Msg) (*sdk.TxResponse, error) {
+    c.mu.Lock()
+    res, err := c.sign(ctx, from, msg)
+    c.mu.Unlock()
+    if err != nil {
+        return nil, err
+    }
+    return c.waitForTx(ctx, res.TxHash)
+}
+
+func (c *HTLCClient) sign(ctx context.Context, from string, msg sdk.Msg) (*sdk.TxResponse, error) {
+    addr, err := c.address(from)
+    if err != nil {
+        return nil, err
+    }
+    accountNumber, sequence, err := c.chain.Account(ctx, addr)
+    if err != nil {
+        return nil, err
+    }
+    if next, ok := c.sequences[addr.String()]; ok && next > sequence {
+        sequence = next
+    }
+
+    factory := tx.Factory{}.
+        WithChainID(c.cfg.ChainID).
+        WithKeybase(c.keyring).
+        WithTxConfig(c.txConfig).
+        WithAccountNumber(accountNumber).
+        WithSequence(sequence).
+        WithGas(c.cfg.GasLimit).
+        WithFees(c.cfg.Fees.String()).
+        WithSignMode(signing.SignMode_SIGN_MODE_DIRECT)
+
+    builder, err := factory.BuildUnsignedTx(msg)
+    if err != nil {
+        return nil, err
+    }
+    if err := tx.Sign(ctx, factory, from, builder, true); err != nil {
+        return nil, err
+    }
+    txBytes, err := c.txConfig.TxEncoder()(builder.GetTx())
+    if err != nil {
+        return nil, err
+    }
+
+    res, err := c.chain.BroadcastTx(ctx, txBytes)
+    if err != nil {
+        return nil, err
+    }
+    if res.Code != 0 {
+        // Whatever got the local sequence out of step, the committed one is
+        // the best guess for the next tx
+        delete(c.sequences, addr.String())
+        return res, sdkerrors.ABCIError(res.Codespace, res.Code, res.RawLog)
+    }
+    c.sequences[addr.String()] = sequence + 1
+    return res, nil
+}
+
+func (c *HTLCClient) waitForTx(ctx context.Context, hash string) (*sdk.TxResponse, error) {
+    ticker := time.NewTicker(c.cfg.PollInterval)
+    defer ticker.Stop()
+
+    for {
+        res, err := c.chain.GetTx(ctx, hash)
+        if err != nil {
+            return nil, err
+        }
+        if res != nil {
+            if res.Code != 0 {
+                return res, sdkerrors.ABCIError(res.Codespace, res.Code, res.RawLog)
+            }
+            return res, nil
+        }
+
+        select {
+        case <-ctx.Done():
+            return nil, ctx.Err()
+        case <-ticker.C:
+        }
+    }
+}
+
+func findAttribute(res *sdk.TxResponse, eventType, key string) (string, bool) {
+    for _, event := range res.Events {
+        if event.Type != eventType {
+            continue
+        }
+        for _, attr := range event.Attributes {
+            if attr.Key == key {
+                return attr.Value, true
+            }
+        }
+    }
+    return "", false
+}
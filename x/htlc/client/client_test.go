@@ -0,0 +1,172 @@
+// x/htlc/client/client_test.go
+package client_test
+
+import (
+    "bytes"
+    "context"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/require"
+
+    "github.com/cosmos/cosmos-sdk/codec"
+    codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+    cryptocodec "github.com/cosmos/cosmos-sdk/crypto/codec"
+    "github.com/cosmos/cosmos-sdk/crypto/hd"
+    "github.com/cosmos/cosmos-sdk/crypto/keyring"
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    authtx "github.com/cosmos/cosmos-sdk/x/auth/tx"
+
+    "github.com/your_repo/x/htlc"
+    htlcclient "github.com/your_repo/x/htlc/client"
+    "github.com/your_repo/x/htlc/testutil"
+)
+
+var genesisTime = time.Unix(1_700_000_000, 0).UTC()
+
+type clientFixture struct {
+    chain    *testutil.MockChain
+    client   *htlcclient.HTLCClient
+    sender   sdk.AccAddress
+    receiver sdk.AccAddress
+}
+
+func setupClient(t *testing.T) clientFixture {
+    registry := codectypes.NewInterfaceRegistry()
+    cryptocodec.RegisterInterfaces(registry)
+    htlc.RegisterInterfaces(registry)
+    cdc := codec.NewProtoCodec(registry)
+    txConfig := authtx.NewTxConfig(cdc, authtx.DefaultSignModes)
+
+    kr := keyring.NewInMemory(cdc)
+    addr := func(name string) sdk.AccAddress {
+        record, _, err := kr.NewMnemonic(name, keyring.English, sdk.FullFundraiserPath, keyring.DefaultBIP39Passphrase, hd.Secp256k1)
+        require.NoError(t, err)
+        a, err := record.GetAddress()
+        require.NoError(t, err)
+        return a
+    }
+
+    chain := testutil.NewMockChain(t, txConfig.TxDecoder(), genesisTime)
+    cfg := htlcclient.DefaultConfig("htlc-test")
+    cfg.PollInterval = time.Millisecond
+
+    f := clientFixture{
+        chain:    chain,
+        client:   htlcclient.NewHTLCClient(chain, txConfig, kr, cfg),
+        sender:   addr("sender"),
+        receiver: addr("receiver"),
+    }
+    chain.Bank.FundAccount(f.sender, sdk.NewCoins(sdk.NewInt64Coin("atom", 1000)))
+    return f
+}
+
+func (f clientFixture) create(t *testing.T, secret []byte) string {
+    id, err := f.client.Create(context.Background(), "sender", htlc.MsgCreateHTLC{
+        Receiver: f.receiver,
+        Amount:   sdk.NewCoins(sdk.NewInt64Coin("atom", 100)),
+        HashLock: sdk.Sha256(secret),
+        TimeLock: uint64(genesisTime.Add(2 * time.Hour).Unix()),
+    })
+    require.NoError(t, err)
+    return id
+}
+
+func TestHTLCClient_CreateAndClaim(t *testing.T) {
+    f := setupClient(t)
+    ctx := context.Background()
+    secret := bytes.Repeat([]byte{0xaa}, htlc.SecretLength)
+
+    id := f.create(t, secret)
+    created, err := f.client.Get(ctx, id)
+    require.NoError(t, err)
+    require.Equal(t, f.sender, created.Sender)
+    require.Equal(t, sdk.Sha256(secret), created.HashLock)
+
+    byReceiver, err := f.client.List(ctx, htlcclient.ListFilter{Receiver: f.receiver})
+    require.NoError(t, err)
+    require.Len(t, byReceiver, 1)
+    _, err = f.client.List(ctx, htlcclient.ListFilter{Sender: f.sender, Receiver: f.receiver})
+    require.Error(t, err)
+
+    _, err = f.client.Claim(ctx, "receiver", id, secret, nil)
+    require.NoError(t, err)
+    require.Equal(t, created.Amount, f.chain.Bank.GetAllBalances(f.chain.Context(), f.receiver))
+
+    claimed, err := f.client.Get(ctx, id)
+    require.NoError(t, err)
+    require.True(t, claimed.Claimed)
+}
+
+func TestHTLCClient_WaitForClaim(t *testing.T) {
+    f := setupClient(t)
+    secret := bytes.Repeat([]byte{0xbb}, htlc.SecretLength)
+    id := f.create(t, secret)
+
+    ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+    defer cancel()
+
+    claimErr := make(chan error, 1)
+    go func() {
+        time.Sleep(10 * time.Millisecond)
+        _, err := f.client.Claim(ctx, "receiver", id, secret, nil)
+        claimErr <- err
+    }()
+
+    revealed, err := f.client.WaitForClaim(ctx, sdk.Sha256(secret))
+    require.NoError(t, err)
+    require.Equal(t, secret, revealed.Secret)
+    require.Equal(t, id, revealed.HTLCID)
+    require.NoError(t, <-claimErr)
+
+    // Nothing is revealed for an unknown hashlock
+    short, cancelShort := context.WithTimeout(context.Background(), 20*time.Millisecond)
+    defer cancelShort()
+    _, err = f.client.WaitForClaim(short, sdk.Sha256([]byte("unknown")))
+    require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestHTLCClient_Refund(t *testing.T) {
+    f := setupClient(t)
+    ctx := context.Background()
+    id := f.create(t, bytes.Repeat([]byte{0xcc}, htlc.SecretLength))
+
+    _, err := f.client.Refund(ctx, "sender", id)
+    require.ErrorIs(t, err, htlc.ErrNotExpired)
+
+    f.chain.AdvanceTime(3 * time.Hour)
+    _, err = f.client.Refund(ctx, "sender", id)
+    require.NoError(t, err)
+    require.Equal(t, sdk.NewCoins(sdk.NewInt64Coin("atom", 1000)), f.chain.Bank.GetAllBalances(f.chain.Context(), f.sender))
+}
+
+func TestHTLCClient_ConcurrentSendsFromOneKey(t *testing.T) {
+    f := setupClient(t)
+    secrets := [][]byte{bytes.Repeat([]byte{0xd1}, htlc.SecretLength), bytes.Repeat([]byte{0xd2}, htlc.SecretLength)}
+    ids := make([]string, len(secrets))
+    for i, secret := range secrets {
+        ids[i] = f.create(t, secret)
+        f.chain.AdvanceTime(time.Second)
+    }
+
+    // Both claims are signed while the first is still in the mempool, so the
+    // committed sequence is stale for the second one
+    f.chain.DeferCommits()
+    ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+    defer cancel()
+
+    claimErrs := make(chan error, len(ids))
+    for i := range ids {
+        go func(i int) {
+            _, err := f.client.Claim(ctx, "receiver", ids[i], secrets[i], nil)
+            claimErrs <- err
+        }(i)
+    }
+    require.Eventually(t, func() bool { return f.chain.PendingTxs() == len(ids) }, time.Second, time.Millisecond)
+    f.chain.Commit()
+
+    for range ids {
+        require.NoError(t, <-claimErrs)
+    }
+    require.Equal(t, sdk.NewCoins(sdk.NewInt64Coin("atom", 200)), f.chain.Bank.GetAllBalances(f.chain.Context(), f.receiver))
+}
@@ -0,0 +1,62 @@
+// x/htlc/client/grpc.go
+package client
+
+import (
+    "context"
+
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+    authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+)
+
+// GRPCChain is a Chain backed by a node's gRPC endpoint
+type GRPCChain struct {
+    *grpc.ClientConn
+
+    auth authtypes.QueryClient
+    tx   txtypes.ServiceClient
+}
+
+var _ Chain = GRPCChain{}
+
+func NewGRPCChain(conn *grpc.ClientConn) GRPCChain {
+    return GRPCChain{
+        ClientConn: conn,
+        auth:       authtypes.NewQueryClient(conn),
+        tx:         txtypes.NewServiceClient(conn),
+    }
+}
+
+func (c GRPCChain) Account(ctx context.Context, addr sdk.AccAddress) (uint64, uint64, error) {
+    res, err := c.auth.AccountInfo(ctx, &authtypes.QueryAccountInfoRequest{Address: addr.String()})
+    if err != nil {
+        return 0, 0, err
+    }
+    return res.Info.AccountNumber, res.Info.Sequence, nil
+}
+
+func (c GRPCChain) BroadcastTx(ctx context.Context, txBytes []byte) (*sdk.TxResponse, error) {
+    res, err := c.tx.BroadcastTx(ctx, &txtypes.BroadcastTxRequest{
+        TxBytes: txBytes,
+        Mode:    txtypes.BroadcastMode_BROADCAST_MODE_SYNC,
+    })
+    if err != nil {
+        return nil, err
+    }
+    return res.TxResponse, nil
+}
+
+func (c GRPCChain) GetTx(ctx context.Context, hash string) (*sdk.TxResponse, error) {
+    res, err := c.tx.GetTx(ctx, &txtypes.GetTxRequest{Hash: hash})
+    if status.Code(err) == codes.NotFound {
+        return nil, nil
+    }
+    if err != nil {
+        return nil, err
+    }
+    return res.TxResponse, nil
+}
@@ -0,0 +1,40 @@
+// x/htlc/codec.go
+package htlc
+
+import (
+    codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    "github.com/cosmos/cosmos-sdk/types/msgservice"
+    "github.com/cosmos/cosmos-sdk/x/authz"
+)
+
+// Type URLs of the htlc messages, as referenced by authz grants
+const (
+    TypeURLMsgCreateHTLC = "/htlc.MsgCreateHTLC"
+    TypeURLMsgClaimHTLC  = "/htlc.MsgClaimHTLC"
+    TypeURLMsgRefundHTLC = "/htlc.MsgRefundHTLC"
+)
+
+// RegisterInterfaces registers the htlc messages and the HTLCAuthorization
+// so they can be packed into Any, e.g. inside an authz MsgExec
+func RegisterInterfaces(registry codectypes.InterfaceRegistry) {
+    registry.RegisterImplementations((*sdk.Msg)(nil),
+        &MsgCreateHTLC{},
+        &MsgClaimHTLC{},
+        &MsgRefundHTLC{},
+        &MsgBatchCreate{},
+        &MsgBatchClaim{},
+        &MsgBatchRefund{},
+        &MsgRescueFunds{},
+        &MsgRescueHTLC{},
+        &MsgRelayClaim{},
+        &MsgVerifyEscrow{},
+        &MsgCreateRoute{},
+        &MsgUpdateParams{},
+    )
+    registry.RegisterImplementations((*authz.Authorization)(nil),
+        &HTLCAuthorization{},
+    )
+
+    msgservice.RegisterMsgServiceDesc(registry, &_Msg_serviceDesc)
+}
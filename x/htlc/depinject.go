@@ -0,0 +1,110 @@
+// x/htlc/depinject.go
+package htlc
+
+import (
+    "fmt"
+    "sort"
+
+    "cosmossdk.io/core/appmodule"
+    "cosmossdk.io/depinject"
+    storetypes "cosmossdk.io/store/types"
+    feegrantkeeper "cosmossdk.io/x/feegrant/keeper"
+
+    "github.com/cosmos/cosmos-sdk/codec"
+    authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+    govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+
+    modulev1 "github.com/your_repo/api/htlc/module/v1"
+)
+
+var _ appmodule.AppModule = AppModule{}
+
+// IsOnePerModuleType implements the depinject.OnePerModuleType interface
+func (AppModule) IsOnePerModuleType() {}
+
+// IsAppModule implements the appmodule.AppModule interface
+func (AppModule) IsAppModule() {}
+
+func init() {
+    appmodule.Register(
+        &modulev1.Module{},
+        appmodule.Provide(ProvideModule),
+        appmodule.Invoke(InvokeSetHTLCHooks),
+    )
+}
+
+type ModuleInputs struct {
+    depinject.In
+
+    Config   *modulev1.Module
+    Cdc      codec.Codec
+    StoreKey *storetypes.KVStoreKey
+
+    BankKeeper        BankKeeper
+    FeegrantKeeper    FeegrantKeeper    `optional:"true"`
+    LightClientKeeper LightClientKeeper `optional:"true"`
+}
+
+type ModuleOutputs struct {
+    depinject.Out
+
+    HTLCKeeper *Keeper
+    Module     appmodule.AppModule
+}
+
+// ProvideModule builds the htlc keeper and module from the app config. The
+// authority defaults to the x/gov module account.
+func ProvideModule(in ModuleInputs) ModuleOutputs {
+    authority := authtypes.NewModuleAddress(govtypes.ModuleName)
+    if in.Config.Authority != "" {
+        authority = authtypes.NewModuleAddressOrBech32Address(in.Config.Authority)
+    }
+
+    k := NewKeeper(in.Cdc, in.StoreKey, in.BankKeeper, in.FeegrantKeeper, authority.String())
+    if in.LightClientKeeper != nil {
+        k.SetLightClientKeeper(in.LightClientKeeper)
+    }
+    // Allowances are revoked through the feegrant Msg service of the same keeper
+    if fk, ok := in.FeegrantKeeper.(feegrantkeeper.Keeper); ok {
+        k.SetFeegrantMsgServer(feegrantkeeper.NewMsgServerImpl(fk))
+    }
+    m := NewAppModule(in.Cdc, &k)
+
+    return ModuleOutputs{HTLCKeeper: &k, Module: m}
+}
+
+// InvokeSetHTLCHooks installs the HTLCHooks other modules provide, in the
+// config's hooks_order or else sorted by module name
+func InvokeSetHTLCHooks(config *modulev1.Module, keeper *Keeper, htlcHooks map[string]HTLCHooksWrapper) error {
+    // all arguments to invokers are optional
+    if keeper == nil || config == nil {
+        return nil
+    }
+
+    modNames := make([]string, 0, len(htlcHooks))
+    for name := range htlcHooks {
+        modNames = append(modNames, name)
+    }
+    order := config.HooksOrder
+    if len(order) == 0 {
+        order = modNames
+        sort.Strings(order)
+    }
+    if len(order) != len(modNames) {
+        return fmt.Errorf("len(hooks_order: %v) != len(hooks modules: %v)", order, modNames)
+    }
+    if len(modNames) == 0 {
+        return nil
+    }
+
+    var multiHooks MultiHTLCHooks
+    for _, modName := range order {
+        hooks, ok := htlcHooks[modName]
+        if !ok {
+            return fmt.Errorf("can't find htlc hooks for module %s", modName)
+        }
+        multiHooks = append(multiHooks, hooks)
+    }
+    keeper.SetHooks(multiHooks)
+    return nil
+}
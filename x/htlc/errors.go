@@ -0,0 +1,36 @@
+// x/htlc/errors.go
+package htlc
+
+import (
+    sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// Codespace of the htlc module errors
+const Codespace = "htlc"
+
+// Registered htlc errors. Codes are part of the module's API: clients such as
+// the TypeScript resolver branch on them, so never renumber an existing error.
+// They mirror the custom errors of IBaseEscrow where one exists.
+var (
+    ErrHTLCNotFound          = sdkerrors.Register(Codespace, 2, "htlc not found")
+    ErrHTLCExists            = sdkerrors.Register(Codespace, 3, "htlc already exists")
+    ErrAlreadyClaimed        = sdkerrors.Register(Codespace, 4, "htlc already claimed")
+    ErrAlreadyRefunded       = sdkerrors.Register(Codespace, 5, "htlc already refunded")
+    ErrAlreadyRescued        = sdkerrors.Register(Codespace, 6, "htlc already rescued")
+    ErrExpired               = sdkerrors.Register(Codespace, 7, "htlc expired")
+    ErrNotExpired            = sdkerrors.Register(Codespace, 8, "htlc not expired")
+    ErrInvalidSecret         = sdkerrors.Register(Codespace, 9, "invalid secret")
+    ErrInvalidProof          = sdkerrors.Register(Codespace, 10, "invalid merkle proof")
+    ErrSecretReused          = sdkerrors.Register(Codespace, 11, "secret already used")
+    ErrNotReceiver           = sdkerrors.Register(Codespace, 12, "caller is not the receiver")
+    ErrNotSender             = sdkerrors.Register(Codespace, 13, "caller is not the sender")
+    ErrHashLockInUse         = sdkerrors.Register(Codespace, 14, "hashlock already used by an open htlc")
+    ErrSecretNotRevealed     = sdkerrors.Register(Codespace, 15, "secret not revealed")
+    ErrInvalidTransition     = sdkerrors.Register(Codespace, 16, "invalid htlc status transition")
+    ErrHeaderNotFound        = sdkerrors.Register(Codespace, 17, "light client header not found")
+    ErrInvalidEscrowProof    = sdkerrors.Register(Codespace, 18, "invalid escrow proof")
+    ErrEscrowNotVerified     = sdkerrors.Register(Codespace, 19, "escrow not verified")
+    ErrEscrowMismatch        = sdkerrors.Register(Codespace, 20, "external ID does not match the escrow address")
+    ErrRouteNotFound         = sdkerrors.Register(Codespace, 21, "route not found")
+    ErrRescueDelayNotElapsed = sdkerrors.Register(Codespace, 22, "rescue delay not elapsed")
+)
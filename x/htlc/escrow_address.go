@@ -0,0 +1,123 @@
+// x/htlc/escrow_address.go
+package htlc
+
+import (
+    "bytes"
+
+    "github.com/ethereum/go-ethereum/common"
+    "github.com/ethereum/go-ethereum/crypto"
+
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+func (f EscrowFactory) Validate() error {
+    for _, addr := range []string{f.Address, f.SrcImplementation, f.DstImplementation} {
+        if !common.IsHexAddress(addr) {
+            return sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "invalid escrow factory address %q for %s", addr, f.Chain)
+        }
+    }
+    return nil
+}
+
+// EscrowAddresses returns the addresses the factory deploys the source and
+// the destination escrow with immutables to, as addressOfEscrowSrc and
+// addressOfEscrowDst compute them
+func (f EscrowFactory) EscrowAddresses(immutables EscrowImmutables) (src, dst common.Address) {
+    factory := common.HexToAddress(f.Address)
+    var salt [32]byte
+    copy(salt[:], immutables.Hash())
+    src = crypto.CreateAddress2(factory, salt, ProxyBytecodeHash(common.HexToAddress(f.SrcImplementation)))
+    dst = crypto.CreateAddress2(factory, salt, ProxyBytecodeHash(common.HexToAddress(f.DstImplementation)))
+    return src, dst
+}
+
+// ProxyBytecodeHash returns the hash of the EIP-1167 proxy creation code for
+// implementation, as ProxyHashLib.computeProxyBytecodeHash does
+func ProxyBytecodeHash(implementation common.Address) []byte {
+    code := common.FromHex("0x3d602d80600a3d3981f3363d3d373d3d3d363d73")
+    code = append(code, implementation.Bytes()...)
+    code = append(code, common.FromHex("0x5af43d82803e903d91602b57fd5bf3")...)
+    return crypto.Keccak256(code)
+}
+
+func (i EscrowImmutables) ValidateBasic() error {
+    if len(i.OrderHash) != common.HashLength || len(i.HashLock) != common.HashLength {
+        return sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "escrow order hash and hashlock must be %d bytes", common.HashLength)
+    }
+    for _, addr := range []string{i.Maker, i.Taker, i.Token} {
+        if !common.IsHexAddress(addr) {
+            return sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "invalid escrow immutables address %q", addr)
+        }
+    }
+    for _, n := range []sdk.Int{i.Amount, i.SafetyDeposit, i.Timelocks} {
+        if n.IsNil() || n.IsNegative() || n.BigInt().BitLen() > 256 {
+            return sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "escrow immutables amounts must be uint256")
+        }
+    }
+    return nil
+}
+
+// Hash returns the CREATE2 salt of the escrow, keccak256 of the ABI encoded
+// immutables as ImmutablesLib.hash computes it
+func (i EscrowImmutables) Hash() []byte {
+    words := [][]byte{
+        i.OrderHash,
+        i.HashLock,
+        common.LeftPadBytes(common.HexToAddress(i.Maker).Bytes(), 32),
+        common.LeftPadBytes(common.HexToAddress(i.Taker).Bytes(), 32),
+        common.LeftPadBytes(common.HexToAddress(i.Token).Bytes(), 32),
+        uint256Word(i.Amount),
+        uint256Word(i.SafetyDeposit),
+        uint256Word(i.Timelocks),
+    }
+    return crypto.Keccak256(words...)
+}
+
+func uint256Word(n sdk.Int) []byte {
+    return common.LeftPadBytes(n.BigInt().Bytes(), 32)
+}
+
+// validateEscrowAddress checks that msg.ExternalID is where factory deploys
+// an escrow with msg.EscrowImmutables, and that the immutables agree with
+// msg.EscrowTerms when both are set
+func validateEscrowAddress(msg MsgCreateHTLC, factory EscrowFactory) error {
+    if msg.EscrowImmutables == nil {
+        return sdkerrors.Wrapf(ErrEscrowMismatch, "escrow immutables required for an escrow on %s", msg.ExternalChain)
+    }
+    immutables := *msg.EscrowImmutables
+    escrow := common.HexToAddress(msg.ExternalID)
+    src, dst := factory.EscrowAddresses(immutables)
+    if escrow != src && escrow != dst {
+        return sdkerrors.Wrapf(ErrEscrowMismatch, "%s is not deployed by factory %s with these immutables, expected %s or %s", escrow, factory.Address, src, dst)
+    }
+
+    terms := msg.EscrowTerms
+    if terms == nil {
+        return nil
+    }
+    switch {
+    case common.HexToAddress(terms.Factory) != common.HexToAddress(factory.Address):
+        return sdkerrors.Wrapf(ErrEscrowMismatch, "escrow terms factory %s is not the registered %s", terms.Factory, factory.Address)
+    case !bytes.Equal(terms.HashLock, immutables.HashLock):
+        return sdkerrors.Wrap(ErrEscrowMismatch, "escrow terms and immutables hashlocks differ")
+    case common.HexToAddress(terms.Token) != common.HexToAddress(immutables.Token):
+        return sdkerrors.Wrap(ErrEscrowMismatch, "escrow terms and immutables tokens differ")
+    case terms.Amount.BigInt().Cmp(immutables.Amount.BigInt()) != 0:
+        return sdkerrors.Wrap(ErrEscrowMismatch, "escrow terms and immutables amounts differ")
+    }
+    return nil
+}
+
+// checkEscrowHashLock gates claims of HTLCs created with escrow immutables.
+// The CREATE2 check only ties ExternalID to the immutables, so the secret must
+// also open their hashlock for both sides to share it.
+func checkEscrowHashLock(htlc HTLC, secret []byte) error {
+    if len(htlc.EscrowHashLock) == 0 {
+        return nil
+    }
+    if !bytes.Equal(crypto.Keccak256(secret), htlc.EscrowHashLock) {
+        return sdkerrors.Wrap(ErrInvalidSecret, "secret does not match the escrow immutables hashlock")
+    }
+    return nil
+}
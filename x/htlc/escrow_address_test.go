@@ -0,0 +1,77 @@
+// x/htlc/escrow_address_test.go
+package htlc_test
+
+import (
+    "time"
+
+    "github.com/ethereum/go-ethereum/crypto"
+
+    sdk "github.com/cosmos/cosmos-sdk/types"
+
+    "github.com/your_repo/x/htlc"
+)
+
+// CreateImmutablesHTLC registers an escrow factory for ethereum and creates an
+// HTLC under secret whose counterpart is the source escrow with immutables
+// locked under escrowSecret
+func (s *KeeperTestSuite) CreateImmutablesHTLC(secret, escrowSecret []byte) htlc.HTLC {
+    factory := htlc.EscrowFactory{
+        Chain:             "ethereum",
+        Address:           escrowFactory.Hex(),
+        SrcImplementation: "0x00000000000000000000000000000000000000a1",
+        DstImplementation: "0x00000000000000000000000000000000000000a2",
+    }
+    params := s.keeper.GetParams(s.ctx)
+    params.EscrowFactories = []htlc.EscrowFactory{factory}
+    s.Require().NoError(s.keeper.SetParams(s.ctx, params))
+
+    immutables := htlc.EscrowImmutables{
+        OrderHash:     crypto.Keccak256([]byte("order")),
+        HashLock:      crypto.Keccak256(escrowSecret),
+        Maker:         "0x0000000000000000000000000000000000000001",
+        Taker:         escrowTaker.Hex(),
+        Token:         escrowToken.Hex(),
+        Amount:        sdk.NewInt(500),
+        SafetyDeposit: sdk.NewInt(10),
+        Timelocks:     sdk.NewInt(s.ctx.BlockTime().Unix()),
+    }
+    src, _ := factory.EscrowAddresses(immutables)
+    msg := htlc.MsgCreateHTLC{
+        Sender:           s.sender,
+        Receiver:         s.receiver,
+        Amount:           sdk.NewCoins(sdk.NewInt64Coin("atom", 100)),
+        HashLock:         sdk.Sha256(secret),
+        TimeLock:         uint64(s.ctx.BlockTime().Add(2 * time.Hour).Unix()),
+        ExternalChain:    "ethereum",
+        ExternalID:       src.Hex(),
+        EscrowImmutables: &immutables,
+    }
+    s.Require().NoError(msg.ValidateBasic())
+    id, err := s.keeper.CreateHTLC(s.ctx, msg)
+    s.Require().NoError(err)
+
+    created, err := s.keeper.GetHTLC(s.ctx, id)
+    s.Require().NoError(err)
+    s.Require().Equal(immutables.HashLock, created.EscrowHashLock)
+    return created
+}
+
+func (s *KeeperTestSuite) TestClaim_SecretMustOpenEscrowImmutables() {
+    secret := s.escrowSecret()
+    created := s.CreateImmutablesHTLC(secret, secret)
+    s.AdvanceTime(time.Minute)
+    s.Require().NoError(s.claim(created.ID, s.receiver, secret, nil))
+}
+
+func (s *KeeperTestSuite) TestClaim_RejectsSpoofedEscrowHashLock() {
+    // The counterpart is a real escrow address, but of an order under
+    // another secret, so revealing this HTLC's secret doesn't open it
+    secret := s.escrowSecret()
+    created := s.CreateImmutablesHTLC(secret, []byte("another escrow secret"))
+    s.AdvanceTime(time.Minute)
+
+    s.Require().ErrorIs(s.claim(created.ID, s.receiver, secret, nil), htlc.ErrInvalidSecret)
+    stored, err := s.keeper.GetHTLC(s.ctx, created.ID)
+    s.Require().NoError(err)
+    s.Require().False(stored.Claimed)
+}
@@ -0,0 +1,130 @@
+// x/htlc/escrow_proof_test.go
+package htlc_test
+
+import (
+    "math/big"
+    "time"
+
+    "github.com/ethereum/go-ethereum/common"
+    "github.com/ethereum/go-ethereum/crypto"
+
+    sdk "github.com/cosmos/cosmos-sdk/types"
+
+    "github.com/your_repo/x/htlc"
+    "github.com/your_repo/x/htlc/testutil"
+)
+
+var (
+    escrowFactory = common.HexToAddress("0x00000000000000000000000000000000000000fa")
+    escrowAddress = common.HexToAddress("0x00000000000000000000000000000000000000e5")
+    escrowToken   = common.HexToAddress("0x00000000000000000000000000000000000000c0")
+    escrowTaker   = common.HexToAddress("0x0000000000000000000000000000000000000007")
+    blockHash     = crypto.Keccak256([]byte("block"))
+)
+
+// CreateEscrowHTLC creates an HTLC whose claim waits for a proof of the
+// EscrowDst at escrowAddress holding 500 of token
+func (s *KeeperTestSuite) CreateEscrowHTLC(secret []byte, token common.Address) htlc.HTLC {
+    msg := htlc.MsgCreateHTLC{
+        Sender:        s.sender,
+        Receiver:      s.receiver,
+        Amount:        sdk.NewCoins(sdk.NewInt64Coin("atom", 100)),
+        HashLock:      sdk.Sha256(secret),
+        TimeLock:      uint64(s.ctx.BlockTime().Add(2 * time.Hour).Unix()),
+        ExternalChain: "ethereum",
+        ExternalID:    escrowAddress.Hex(),
+        EscrowTerms: &htlc.EscrowTerms{
+            Factory:  escrowFactory.Hex(),
+            HashLock: crypto.Keccak256(secret),
+            Token:    token.Hex(),
+            Amount:   sdk.NewInt(500),
+        },
+    }
+    s.Require().NoError(msg.ValidateBasic())
+    id, err := s.keeper.CreateHTLC(s.ctx, msg)
+    s.Require().NoError(err)
+
+    created, err := s.keeper.GetHTLC(s.ctx, id)
+    s.Require().NoError(err)
+    return created
+}
+
+func (s *KeeperTestSuite) escrowSecret() []byte {
+    secret := make([]byte, htlc.SecretLength)
+    copy(secret, "escrow secret")
+    return secret
+}
+
+func (s *KeeperTestSuite) TestVerifyEscrow_TokenEscrowUnlocksClaim() {
+    lc := testutil.NewMockLightClient()
+    s.keeper.SetLightClientKeeper(lc)
+    secret := s.escrowSecret()
+    created := s.CreateEscrowHTLC(secret, escrowToken)
+
+    s.Require().ErrorIs(s.claim(created.ID, s.receiver, secret, nil), htlc.ErrEscrowNotVerified)
+
+    receipts := testutil.ReceiptTrie(
+        testutil.SuccessfulReceipt(),
+        testutil.SuccessfulReceipt(
+            testutil.DstEscrowCreatedLog(escrowFactory, escrowAddress, crypto.Keccak256(secret), escrowTaker),
+            testutil.TransferLog(escrowToken, escrowTaker, escrowAddress, big.NewInt(500)),
+        ),
+    )
+    lc.SetHeader("ethereum", blockHash, htlc.EthHeader{Number: 7, ReceiptsRoot: receipts.Root()})
+    msg := htlc.NewMsgVerifyEscrow(s.other, created.ID, blockHash, 1, receipts.Prove(testutil.ReceiptKey(1)), nil)
+    s.Require().NoError(msg.ValidateBasic())
+    s.Require().NoError(s.keeper.VerifyEscrow(s.ctx, msg))
+
+    verified, err := s.keeper.GetHTLC(s.ctx, created.ID)
+    s.Require().NoError(err)
+    s.Require().True(verified.EscrowVerified)
+    s.Require().NoError(s.claim(created.ID, s.receiver, secret, nil))
+}
+
+func (s *KeeperTestSuite) TestVerifyEscrow_NativeEscrowBalance() {
+    lc := testutil.NewMockLightClient()
+    s.keeper.SetLightClientKeeper(lc)
+    secret := s.escrowSecret()
+    created := s.CreateEscrowHTLC(secret, common.Address{})
+
+    receipts := testutil.ReceiptTrie(testutil.SuccessfulReceipt(
+        testutil.DstEscrowCreatedLog(escrowFactory, escrowAddress, crypto.Keccak256(secret), escrowTaker),
+    ))
+    short := testutil.StateTrie(map[common.Address]*big.Int{escrowAddress: big.NewInt(499)})
+    lc.SetHeader("ethereum", blockHash, htlc.EthHeader{StateRoot: short.Root(), ReceiptsRoot: receipts.Root()})
+    msg := htlc.NewMsgVerifyEscrow(s.other, created.ID, blockHash, 0, receipts.Prove(testutil.ReceiptKey(0)), short.Prove(testutil.AccountKey(escrowAddress)))
+    s.Require().ErrorIs(s.keeper.VerifyEscrow(s.ctx, msg), htlc.ErrInvalidEscrowProof)
+
+    funded := testutil.StateTrie(map[common.Address]*big.Int{escrowAddress: big.NewInt(510)})
+    lc.SetHeader("ethereum", blockHash, htlc.EthHeader{StateRoot: funded.Root(), ReceiptsRoot: receipts.Root()})
+    msg.AccountProof = funded.Prove(testutil.AccountKey(escrowAddress))
+    s.Require().NoError(s.keeper.VerifyEscrow(s.ctx, msg))
+}
+
+func (s *KeeperTestSuite) TestVerifyEscrow_RejectsMismatchedDeployment() {
+    lc := testutil.NewMockLightClient()
+    s.keeper.SetLightClientKeeper(lc)
+    secret := s.escrowSecret()
+    created := s.CreateEscrowHTLC(secret, escrowToken)
+    transfer := testutil.TransferLog(escrowToken, escrowTaker, escrowAddress, big.NewInt(500))
+
+    for name, receipt := range map[string]*testutil.EthTrie{
+        "other hashlock": testutil.ReceiptTrie(testutil.SuccessfulReceipt(
+            testutil.DstEscrowCreatedLog(escrowFactory, escrowAddress, crypto.Keccak256([]byte("other")), escrowTaker), transfer)),
+        "other factory": testutil.ReceiptTrie(testutil.SuccessfulReceipt(
+            testutil.DstEscrowCreatedLog(escrowTaker, escrowAddress, crypto.Keccak256(secret), escrowTaker), transfer)),
+        "short transfer": testutil.ReceiptTrie(testutil.SuccessfulReceipt(
+            testutil.DstEscrowCreatedLog(escrowFactory, escrowAddress, crypto.Keccak256(secret), escrowTaker),
+            testutil.TransferLog(escrowToken, escrowTaker, escrowAddress, big.NewInt(499)))),
+    } {
+        lc.SetHeader("ethereum", blockHash, htlc.EthHeader{ReceiptsRoot: receipt.Root()})
+        msg := htlc.NewMsgVerifyEscrow(s.other, created.ID, blockHash, 0, receipt.Prove(testutil.ReceiptKey(0)), nil)
+        s.Require().ErrorIs(s.keeper.VerifyEscrow(s.ctx, msg), htlc.ErrInvalidEscrowProof, name)
+    }
+
+    // A valid proof against a block the light client doesn't know
+    receipt := testutil.ReceiptTrie(testutil.SuccessfulReceipt(
+        testutil.DstEscrowCreatedLog(escrowFactory, escrowAddress, crypto.Keccak256(secret), escrowTaker), transfer))
+    msg := htlc.NewMsgVerifyEscrow(s.other, created.ID, crypto.Keccak256([]byte("unknown")), 0, receipt.Prove(testutil.ReceiptKey(0)), nil)
+    s.Require().ErrorIs(s.keeper.VerifyEscrow(s.ctx, msg), htlc.ErrHeaderNotFound)
+}
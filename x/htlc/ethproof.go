@@ -0,0 +1,149 @@
+// x/htlc/ethproof.go
+package htlc
+
+import (
+    "bytes"
+    "errors"
+    "fmt"
+    "math/big"
+
+    "github.com/ethereum/go-ethereum/common"
+    ethtypes "github.com/ethereum/go-ethereum/core/types"
+    "github.com/ethereum/go-ethereum/crypto"
+    "github.com/ethereum/go-ethereum/ethdb/memorydb"
+    "github.com/ethereum/go-ethereum/rlp"
+    "github.com/ethereum/go-ethereum/trie"
+)
+
+// Log topics of the escrow deployment. The Address type of solidity-utils is
+// a uint256, so it appears as such in the DstEscrowCreated signature.
+var (
+    DstEscrowCreatedTopic = crypto.Keccak256Hash([]byte("DstEscrowCreated(address,bytes32,uint256)"))
+    TransferTopic         = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+)
+
+// EthHeader is the part of an execution-layer header, as verified by the
+// light client, that escrow proofs are checked against
+type EthHeader struct {
+    Number       uint64
+    StateRoot    []byte
+    ReceiptsRoot []byte
+}
+
+// ethAccount is the RLP layout of an account in the state trie
+type ethAccount struct {
+    Nonce    uint64
+    Balance  *big.Int
+    Root     common.Hash
+    CodeHash []byte
+}
+
+// verifyTrieProof returns the value stored under key in the Merkle-Patricia
+// trie with the given root. proof holds the RLP encoded nodes on the path.
+func verifyTrieProof(root, key []byte, proof [][]byte) ([]byte, error) {
+    db := memorydb.New()
+    for _, node := range proof {
+        if err := db.Put(crypto.Keccak256(node), node); err != nil {
+            return nil, err
+        }
+    }
+    value, err := trie.VerifyProof(common.BytesToHash(root), key, db)
+    if err != nil {
+        return nil, err
+    }
+    // A valid proof of absence returns no value
+    if len(value) == 0 {
+        return nil, errors.New("key is not in the trie")
+    }
+    return value, nil
+}
+
+// verifyReceipt returns the receipt of the txIndex-th transaction of the block
+func verifyReceipt(header EthHeader, txIndex uint64, proof [][]byte) (*ethtypes.Receipt, error) {
+    key, err := rlp.EncodeToBytes(txIndex)
+    if err != nil {
+        return nil, err
+    }
+    value, err := verifyTrieProof(header.ReceiptsRoot, key, proof)
+    if err != nil {
+        return nil, fmt.Errorf("receipt proof: %w", err)
+    }
+    receipt := new(ethtypes.Receipt)
+    if err := receipt.UnmarshalBinary(value); err != nil {
+        return nil, fmt.Errorf("receipt: %w", err)
+    }
+    return receipt, nil
+}
+
+// verifyAccount returns the state of addr at the block
+func verifyAccount(header EthHeader, addr common.Address, proof [][]byte) (ethAccount, error) {
+    var account ethAccount
+    value, err := verifyTrieProof(header.StateRoot, crypto.Keccak256(addr.Bytes()), proof)
+    if err != nil {
+        return account, fmt.Errorf("account proof: %w", err)
+    }
+    if err := rlp.DecodeBytes(value, &account); err != nil {
+        return account, fmt.Errorf("account: %w", err)
+    }
+    return account, nil
+}
+
+// verifyEscrowDeployment checks that the receipt emits DstEscrowCreated from
+// the factory for escrow with the terms' hashlock, and that the escrow holds
+// the terms' amount: through the token Transfer of the same receipt, or for
+// the native token through the escrow balance in the state trie
+func verifyEscrowDeployment(header EthHeader, terms EscrowTerms, escrow common.Address, msg MsgVerifyEscrow) error {
+    receipt, err := verifyReceipt(header, msg.TxIndex, msg.ReceiptProof)
+    if err != nil {
+        return err
+    }
+    if receipt.Status != ethtypes.ReceiptStatusSuccessful {
+        return errors.New("escrow deployment reverted")
+    }
+
+    factory := common.HexToAddress(terms.Factory)
+    if !findDstEscrowCreated(receipt.Logs, factory, escrow, terms.HashLock) {
+        return fmt.Errorf("no DstEscrowCreated for %s with the expected hashlock from factory %s", escrow, factory)
+    }
+
+    amount := terms.Amount.BigInt()
+    token := common.HexToAddress(terms.Token)
+    if token == (common.Address{}) {
+        account, err := verifyAccount(header, escrow, msg.AccountProof)
+        if err != nil {
+            return err
+        }
+        if account.Balance == nil || account.Balance.Cmp(amount) < 0 {
+            return fmt.Errorf("escrow balance %v is below %s", account.Balance, terms.Amount)
+        }
+        return nil
+    }
+    if !findTransfer(receipt.Logs, token, escrow, amount) {
+        return fmt.Errorf("no transfer of %s %s to %s", terms.Amount, token, escrow)
+    }
+    return nil
+}
+
+func findDstEscrowCreated(logs []*ethtypes.Log, factory, escrow common.Address, hashLock []byte) bool {
+    for _, log := range logs {
+        if log.Address != factory || len(log.Topics) == 0 || log.Topics[0] != DstEscrowCreatedTopic || len(log.Data) != 3*32 {
+            continue
+        }
+        if common.BytesToAddress(log.Data[:32]) == escrow && bytes.Equal(log.Data[32:64], hashLock) {
+            return true
+        }
+    }
+    return false
+}
+
+func findTransfer(logs []*ethtypes.Log, token, to common.Address, amount *big.Int) bool {
+    for _, log := range logs {
+        if log.Address != token || len(log.Topics) != 3 || log.Topics[0] != TransferTopic || len(log.Data) != 32 {
+            continue
+        }
+        if common.BytesToAddress(log.Topics[2].Bytes()) == to && new(big.Int).SetBytes(log.Data).Cmp(amount) == 0 {
+            return true
+        }
+    }
+    return false
+}
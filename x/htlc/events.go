@@ -0,0 +1,25 @@
+// x/htlc/events.go
+package htlc
+
+const (
+    EventTypeStatusChanged  = "htlc_status_changed"
+    EventTypeBatchItem      = "htlc_batch_item"
+    EventTypeEscrowVerified = "htlc_escrow_verified"
+    EventTypeRouteHop       = "htlc_route_hop"
+
+    AttributeKeyID          = "id"
+    AttributeKeyStatus      = "status"
+    AttributeKeyIndex       = "index"
+    AttributeKeySuccess     = "success"
+    AttributeKeyError       = "error"
+    AttributeKeyEscrow      = "escrow"
+    AttributeKeyBlockHash   = "block_hash"
+    AttributeKeyBlockNumber = "block_number"
+    AttributeKeyRouteID     = "route_id"
+    AttributeKeyChain       = "chain"
+    AttributeKeySender      = "sender"
+    AttributeKeyReceiver    = "receiver"
+    AttributeKeyAmount      = "amount"
+    AttributeKeyHashLock    = "hashlock"
+    AttributeKeyTimeLock    = "timelock"
+)
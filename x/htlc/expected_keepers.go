@@ -0,0 +1,37 @@
+// x/htlc/expected_keepers.go
+package htlc
+
+import (
+    "context"
+
+    "cosmossdk.io/x/feegrant"
+
+    sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// BankKeeper defines the bank functionality the htlc module depends on
+type BankKeeper interface {
+    SendCoinsFromAccountToModule(ctx sdk.Context, senderAddr sdk.AccAddress, recipientModule string, amt sdk.Coins) error
+    SendCoinsFromModuleToAccount(ctx sdk.Context, senderModule string, recipientAddr sdk.AccAddress, amt sdk.Coins) error
+    GetAllBalances(ctx sdk.Context, addr sdk.AccAddress) sdk.Coins
+}
+
+// FeegrantKeeper defines the fee allowance functionality used for fee-granted
+// claims
+type FeegrantKeeper interface {
+    GrantAllowance(ctx context.Context, granter, grantee sdk.AccAddress, feeAllowance feegrant.FeeAllowanceI) error
+}
+
+// FeegrantMsgServer revokes the allowances of fee-granted claims. The
+// feegrant keeper keeps its revokeAllowance unexported, so revocation goes
+// through the feegrant Msg service, see feegrantkeeper.NewMsgServerImpl.
+type FeegrantMsgServer interface {
+    RevokeAllowance(ctx context.Context, msg *feegrant.MsgRevokeAllowance) (*feegrant.MsgRevokeAllowanceResponse, error)
+}
+
+// LightClientKeeper defines the on-chain light client escrow proofs are
+// checked against. GetEthHeader returns the verified execution header of
+// blockHash on the external chain, found is false for unknown blocks.
+type LightClientKeeper interface {
+    GetEthHeader(ctx sdk.Context, chain string, blockHash []byte) (header EthHeader, found bool)
+}
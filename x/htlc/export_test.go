@@ -0,0 +1,18 @@
+// x/htlc/export_test.go
+package htlc
+
+import (
+    "github.com/cosmos/cosmos-sdk/codec"
+    "github.com/cosmos/cosmos-sdk/store/prefix"
+    sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Test-only access to keeper internals for the htlc_test package
+
+func (k Keeper) GetStore(ctx sdk.Context) prefix.Store {
+    return k.getHTLCStore(ctx)
+}
+
+func (k Keeper) Cdc() codec.BinaryCodec {
+    return k.cdc
+}
@@ -0,0 +1,100 @@
+// x/htlc/feegrant_test.go
+package htlc_test
+
+import (
+    "time"
+
+    "cosmossdk.io/x/feegrant"
+
+    sdk "github.com/cosmos/cosmos-sdk/types"
+
+    "github.com/your_repo/x/htlc"
+)
+
+// CreateFeeGrantedHTLC creates an HTLC like CreateHTLC whose sender also
+// pays the receiver's claim fees up to 5atom
+func (s *KeeperTestSuite) CreateFeeGrantedHTLC(secret []byte, lock time.Duration) htlc.HTLC {
+    msg := htlc.MsgCreateHTLC{
+        Sender:       s.sender,
+        Receiver:     s.receiver,
+        Amount:       sdk.NewCoins(sdk.NewInt64Coin("atom", 100)),
+        HashLock:     sdk.Sha256(secret),
+        TimeLock:     uint64(s.ctx.BlockTime().Add(lock).Unix()),
+        FeeAllowance: sdk.NewCoins(sdk.NewInt64Coin("atom", 5)),
+    }
+    id, err := s.keeper.CreateHTLC(s.ctx, msg)
+    s.Require().NoError(err)
+
+    created, err := s.keeper.GetHTLC(s.ctx, id)
+    s.Require().NoError(err)
+    s.Require().True(created.FeeGranted)
+    return created
+}
+
+func (s *KeeperTestSuite) TestFeeGrant_GrantedOnCreate() {
+    created := s.CreateFeeGrantedHTLC([]byte("secret"), 2*time.Hour)
+
+    allowance, ok := s.feegrant.Allowance(s.sender, s.receiver).(*feegrant.AllowedMsgAllowance)
+    s.Require().True(ok)
+    s.Require().Equal([]string{htlc.TypeURLMsgClaimHTLC}, allowance.AllowedMessages)
+
+    basic, err := allowance.GetAllowance()
+    s.Require().NoError(err)
+    s.Require().Equal(sdk.NewCoins(sdk.NewInt64Coin("atom", 5)), basic.(*feegrant.BasicAllowance).SpendLimit)
+    s.Require().True(created.TimeLock.Equal(*basic.(*feegrant.BasicAllowance).Expiration))
+
+    // feegrant holds one allowance per pair, so a second one can't be opened
+    s.AdvanceTime(time.Second)
+    msg := htlc.MsgCreateHTLC{
+        Sender:       s.sender,
+        Receiver:     s.receiver,
+        Amount:       sdk.NewCoins(sdk.NewInt64Coin("atom", 100)),
+        HashLock:     sdk.Sha256([]byte("other secret")),
+        TimeLock:     uint64(s.ctx.BlockTime().Add(2 * time.Hour).Unix()),
+        FeeAllowance: sdk.NewCoins(sdk.NewInt64Coin("atom", 5)),
+    }
+    _, err = s.keeper.CreateHTLC(s.ctx, msg)
+    s.Require().Error(err)
+}
+
+func (s *KeeperTestSuite) TestFeeGrant_RevokedOnClaim() {
+    secret := []byte("secret")
+    created := s.CreateFeeGrantedHTLC(secret, 2*time.Hour)
+
+    s.AdvanceTime(time.Minute)
+    s.Require().NoError(s.claim(created.ID, s.receiver, secret, nil))
+    s.Require().Nil(s.feegrant.Allowance(s.sender, s.receiver))
+
+    claimed, err := s.keeper.GetHTLC(s.ctx, created.ID)
+    s.Require().NoError(err)
+    s.Require().False(claimed.FeeGranted)
+
+    // The pair is free for the next fee-granted HTLC
+    s.AdvanceTime(time.Second)
+    s.CreateFeeGrantedHTLC([]byte("next secret"), 2*time.Hour)
+}
+
+func (s *KeeperTestSuite) TestFeeGrant_RevokedOnRefund() {
+    created := s.CreateFeeGrantedHTLC([]byte("secret"), 2*time.Hour)
+
+    s.SetBlockTime(created.TimeLock)
+    s.Require().NoError(s.refund(created.ID, s.sender))
+    s.Require().Nil(s.feegrant.Allowance(s.sender, s.receiver))
+
+    refunded, err := s.keeper.GetHTLC(s.ctx, created.ID)
+    s.Require().NoError(err)
+    s.Require().False(refunded.FeeGranted)
+}
+
+func (s *KeeperTestSuite) TestFeeGrant_AllowanceAlreadyGone() {
+    secret := []byte("secret")
+    created := s.CreateFeeGrantedHTLC(secret, 2*time.Hour)
+
+    // feegrant drops allowances that were used up
+    _, err := s.feegrant.RevokeAllowance(s.ctx, &feegrant.MsgRevokeAllowance{Granter: s.sender.String(), Grantee: s.receiver.String()})
+    s.Require().NoError(err)
+
+    s.AdvanceTime(time.Minute)
+    s.Require().NoError(s.claim(created.ID, s.receiver, secret, nil))
+    s.Require().Equal(created.Amount, s.bank.GetAllBalances(s.ctx, s.receiver))
+}
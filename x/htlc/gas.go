@@ -0,0 +1,16 @@
+// x/htlc/gas.go
+package htlc
+
+// Gas charged by the module on top of the KV store costs, so that work which
+// grows with the message or the record is paid for by the caller. See the
+// gas table in the README.
+const (
+    // GasPerProofNode is charged for every Merkle proof node in a claim,
+    // before the proof is verified, and covers one sha256 over a node pair
+    GasPerProofNode uint64 = 300
+    // GasPerRecordByte is charged for every byte of an HTLC record written
+    // by create, claim, refund or rescue
+    GasPerRecordByte uint64 = 10
+    // GasPerUsedSecret is charged when a partial fill marks its secret used
+    GasPerUsedSecret uint64 = 1000
+)
@@ -0,0 +1,144 @@
+// x/htlc/grpc_query.go
+package htlc
+
+import (
+    "context"
+    "errors"
+
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+type queryServer struct {
+    keeper *Keeper
+}
+
+var _ QueryServer = queryServer{}
+
+// NewQueryServerImpl returns the htlc Query service
+func NewQueryServerImpl(keeper *Keeper) QueryServer {
+    return queryServer{keeper: keeper}
+}
+
+func (s queryServer) HTLC(goCtx context.Context, req *QueryHTLCRequest) (*QueryHTLCResponse, error) {
+    if req == nil {
+        return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "empty request")
+    }
+    htlc, err := s.keeper.GetHTLC(sdk.UnwrapSDKContext(goCtx), req.ID)
+    if err != nil {
+        return nil, notFound(err)
+    }
+    return &QueryHTLCResponse{HTLC: htlc}, nil
+}
+
+func (s queryServer) Status(goCtx context.Context, req *QueryStatusRequest) (*QueryStatusResponse, error) {
+    if req == nil {
+        return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "empty request")
+    }
+    htlcStatus, err := s.keeper.HTLCStatus(sdk.UnwrapSDKContext(goCtx), req.ID)
+    if err != nil {
+        return nil, notFound(err)
+    }
+    return &QueryStatusResponse{ID: req.ID, Status: htlcStatus.String()}, nil
+}
+
+func (s queryServer) HTLCsBySender(goCtx context.Context, req *QueryHTLCsByAddressRequest) (*QueryHTLCsResponse, error) {
+    if req == nil {
+        return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "empty request")
+    }
+    addr, err := sdk.AccAddressFromBech32(req.Address)
+    if err != nil {
+        return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, err.Error())
+    }
+    htlcs, err := s.keeper.GetHTLCsBySender(sdk.UnwrapSDKContext(goCtx), addr)
+    if err != nil {
+        return nil, err
+    }
+    return &QueryHTLCsResponse{HTLCs: htlcs}, nil
+}
+
+func (s queryServer) HTLCsByReceiver(goCtx context.Context, req *QueryHTLCsByAddressRequest) (*QueryHTLCsResponse, error) {
+    if req == nil {
+        return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "empty request")
+    }
+    addr, err := sdk.AccAddressFromBech32(req.Address)
+    if err != nil {
+        return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, err.Error())
+    }
+    htlcs, err := s.keeper.GetHTLCsByReceiver(sdk.UnwrapSDKContext(goCtx), addr)
+    if err != nil {
+        return nil, err
+    }
+    return &QueryHTLCsResponse{HTLCs: htlcs}, nil
+}
+
+func (s queryServer) HTLCsByHashLock(goCtx context.Context, req *QueryByHashLockRequest) (*QueryHTLCsResponse, error) {
+    if req == nil {
+        return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "empty request")
+    }
+    htlcs, err := s.keeper.GetHTLCsByHashLock(sdk.UnwrapSDKContext(goCtx), req.HashLock)
+    if err != nil {
+        return nil, err
+    }
+    return &QueryHTLCsResponse{HTLCs: htlcs}, nil
+}
+
+func (s queryServer) HTLCsByExternalID(goCtx context.Context, req *QueryHTLCsByExternalIDRequest) (*QueryHTLCsResponse, error) {
+    if req == nil {
+        return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "empty request")
+    }
+    htlcs, err := s.keeper.GetHTLCsByExternalID(sdk.UnwrapSDKContext(goCtx), req.ExternalChain, req.ExternalID)
+    if err != nil {
+        return nil, err
+    }
+    return &QueryHTLCsResponse{HTLCs: htlcs}, nil
+}
+
+func (s queryServer) HashLockUsage(goCtx context.Context, req *QueryByHashLockRequest) (*QueryHashLockUsageResponse, error) {
+    if req == nil {
+        return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "empty request")
+    }
+    usage, err := s.keeper.GetHashLockUsage(sdk.UnwrapSDKContext(goCtx), req.HashLock)
+    if err != nil {
+        return nil, err
+    }
+    return &QueryHashLockUsageResponse{Usage: usage}, nil
+}
+
+func (s queryServer) RevealedSecret(goCtx context.Context, req *QueryByHashLockRequest) (*QueryRevealedSecretResponse, error) {
+    if req == nil {
+        return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "empty request")
+    }
+    revealed, err := s.keeper.GetRevealedSecret(sdk.UnwrapSDKContext(goCtx), req.HashLock)
+    if err != nil {
+        return nil, notFound(err)
+    }
+    return &QueryRevealedSecretResponse{Revealed: revealed}, nil
+}
+
+func (s queryServer) Route(goCtx context.Context, req *QueryRouteRequest) (*QueryRouteResponse, error) {
+    if req == nil {
+        return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "empty request")
+    }
+    route, err := s.keeper.RouteStatus(sdk.UnwrapSDKContext(goCtx), req.ID)
+    if err != nil {
+        return nil, notFound(err)
+    }
+    return &route, nil
+}
+
+func (s queryServer) Params(goCtx context.Context, req *QueryParamsRequest) (*QueryParamsResponse, error) {
+    return &QueryParamsResponse{Params: s.keeper.GetParams(sdk.UnwrapSDKContext(goCtx))}, nil
+}
+
+// notFound maps the errors of missing records to the NotFound gRPC code, so
+// clients can tell them from failures without matching error strings
+func notFound(err error) error {
+    if errors.Is(err, ErrHTLCNotFound) || errors.Is(err, ErrRouteNotFound) || errors.Is(err, ErrSecretNotRevealed) {
+        return status.Error(codes.NotFound, err.Error())
+    }
+    return err
+}
@@ -6,41 +6,51 @@ import (
     sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
 )
 
-func NewHandler(k Keeper) sdk.Handler {
+// NewHandler routes htlc messages to the Msg service, for callers delivering
+// messages without a MsgServiceRouter
+func NewHandler(k *Keeper) sdk.Handler {
+    msgServer := NewMsgServerImpl(k)
+
     return func(ctx sdk.Context, msg sdk.Msg) (*sdk.Result, error) {
         switch msg := msg.(type) {
-        case MsgCreateHTLC:
-            return handleMsgCreateHTLC(ctx, k, msg)
-        case MsgClaimHTLC:
-            return handleMsgClaimHTLC(ctx, k, msg)
-        case MsgRefundHTLC:
-            return handleMsgRefundHTLC(ctx, k, msg)
+        case *MsgCreateHTLC:
+            res, err := msgServer.CreateHTLC(ctx, msg)
+            return sdk.WrapServiceResult(ctx, res, err)
+        case *MsgClaimHTLC:
+            res, err := msgServer.ClaimHTLC(ctx, msg)
+            return sdk.WrapServiceResult(ctx, res, err)
+        case *MsgRefundHTLC:
+            res, err := msgServer.RefundHTLC(ctx, msg)
+            return sdk.WrapServiceResult(ctx, res, err)
+        case *MsgBatchCreate:
+            res, err := msgServer.BatchCreate(ctx, msg)
+            return sdk.WrapServiceResult(ctx, res, err)
+        case *MsgBatchClaim:
+            res, err := msgServer.BatchClaim(ctx, msg)
+            return sdk.WrapServiceResult(ctx, res, err)
+        case *MsgBatchRefund:
+            res, err := msgServer.BatchRefund(ctx, msg)
+            return sdk.WrapServiceResult(ctx, res, err)
+        case *MsgRescueFunds:
+            res, err := msgServer.RescueFunds(ctx, msg)
+            return sdk.WrapServiceResult(ctx, res, err)
+        case *MsgRescueHTLC:
+            res, err := msgServer.RescueHTLC(ctx, msg)
+            return sdk.WrapServiceResult(ctx, res, err)
+        case *MsgRelayClaim:
+            res, err := msgServer.RelayClaim(ctx, msg)
+            return sdk.WrapServiceResult(ctx, res, err)
+        case *MsgVerifyEscrow:
+            res, err := msgServer.VerifyEscrow(ctx, msg)
+            return sdk.WrapServiceResult(ctx, res, err)
+        case *MsgCreateRoute:
+            res, err := msgServer.CreateRoute(ctx, msg)
+            return sdk.WrapServiceResult(ctx, res, err)
+        case *MsgUpdateParams:
+            res, err := msgServer.UpdateParams(ctx, msg)
+            return sdk.WrapServiceResult(ctx, res, err)
         default:
             return nil, sdkerrors.Wrapf(sdkerrors.ErrUnknownRequest, "unrecognized htlc message type: %T", msg)
         }
     }
 }
-
-func handleMsgCreateHTLC(ctx sdk.Context, k Keeper, msg MsgCreateHTLC) (*sdk.Result, error) {
-    err := k.CreateHTLC(ctx, msg)
-    if err != nil {
-        return nil, err
-    }
-    return &sdk.Result{Events: ctx.EventManager().Events()}, nil
-}
-
-func handleMsgClaimHTLC(ctx sdk.Context, k Keeper, msg MsgClaimHTLC) (*sdk.Result, error) {
-    err := k.ClaimHTLC(ctx, msg)
-    if err != nil {
-        return nil, err
-    }
-    return &sdk.Result{Events: ctx.EventManager().Events()}, nil
-}
-
-func handleMsgRefundHTLC(ctx sdk.Context, k Keeper, msg MsgRefundHTLC) (*sdk.Result, error) {
-    err := k.RefundHTLC(ctx, msg)
-    if err != nil {
-        return nil, err
-    }
-    return &sdk.Result{Events: ctx.EventManager().Events()}, nil
-}
@@ -0,0 +1,76 @@
+// x/htlc/hooks.go
+package htlc
+
+import (
+    sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// HTLCHooks lets other modules react to HTLC lifecycle events.
+// Returning an error aborts the message that triggered the hook.
+type HTLCHooks interface {
+    AfterHTLCCreated(ctx sdk.Context, htlc HTLC) error
+    AfterHTLCClaimed(ctx sdk.Context, htlc HTLC, secret []byte) error
+    AfterHTLCRefunded(ctx sdk.Context, htlc HTLC) error
+    AfterPartialFill(ctx sdk.Context, htlc HTLC, secret []byte) error
+    AfterHTLCRescued(ctx sdk.Context, htlc HTLC) error
+}
+
+// HTLCHooksWrapper lets modules provide HTLCHooks through depinject
+type HTLCHooksWrapper struct{ HTLCHooks }
+
+// IsOnePerModuleType implements the depinject.OnePerModuleType interface
+func (HTLCHooksWrapper) IsOnePerModuleType() {}
+
+var _ HTLCHooks = MultiHTLCHooks{}
+
+// MultiHTLCHooks combines several HTLCHooks, called in order
+type MultiHTLCHooks []HTLCHooks
+
+func NewMultiHTLCHooks(hooks ...HTLCHooks) MultiHTLCHooks {
+    return hooks
+}
+
+func (h MultiHTLCHooks) AfterHTLCCreated(ctx sdk.Context, htlc HTLC) error {
+    for i := range h {
+        if err := h[i].AfterHTLCCreated(ctx, htlc); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+func (h MultiHTLCHooks) AfterHTLCClaimed(ctx sdk.Context, htlc HTLC, secret []byte) error {
+    for i := range h {
+        if err := h[i].AfterHTLCClaimed(ctx, htlc, secret); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+func (h MultiHTLCHooks) AfterHTLCRefunded(ctx sdk.Context, htlc HTLC) error {
+    for i := range h {
+        if err := h[i].AfterHTLCRefunded(ctx, htlc); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+func (h MultiHTLCHooks) AfterPartialFill(ctx sdk.Context, htlc HTLC, secret []byte) error {
+    for i := range h {
+        if err := h[i].AfterPartialFill(ctx, htlc, secret); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+func (h MultiHTLCHooks) AfterHTLCRescued(ctx sdk.Context, htlc HTLC) error {
+    for i := range h {
+        if err := h[i].AfterHTLCRescued(ctx, htlc); err != nil {
+            return err
+        }
+    }
+    return nil
+}
@@ -0,0 +1,107 @@
+// x/htlc/hooks_test.go
+package htlc_test
+
+import (
+    "errors"
+    "time"
+
+    sdk "github.com/cosmos/cosmos-sdk/types"
+
+    "github.com/your_repo/x/htlc"
+    "github.com/your_repo/x/htlc/testutil"
+)
+
+var errHookFailed = errors.New("hook failed")
+
+// recordingHooks records every hook call as "<hook> <id>" and fails the
+// hook named failOn
+type recordingHooks struct {
+    calls  []string
+    failOn string
+}
+
+func (h *recordingHooks) record(hook string, htlc htlc.HTLC) error {
+    h.calls = append(h.calls, hook+" "+htlc.ID)
+    if hook == h.failOn {
+        return errHookFailed
+    }
+    return nil
+}
+
+func (h *recordingHooks) AfterHTLCCreated(_ sdk.Context, htlc htlc.HTLC) error {
+    return h.record("created", htlc)
+}
+
+func (h *recordingHooks) AfterHTLCClaimed(_ sdk.Context, htlc htlc.HTLC, _ []byte) error {
+    return h.record("claimed", htlc)
+}
+
+func (h *recordingHooks) AfterHTLCRefunded(_ sdk.Context, htlc htlc.HTLC) error {
+    return h.record("refunded", htlc)
+}
+
+func (h *recordingHooks) AfterPartialFill(_ sdk.Context, htlc htlc.HTLC, _ []byte) error {
+    return h.record("partial_fill", htlc)
+}
+
+func (h *recordingHooks) AfterHTLCRescued(_ sdk.Context, htlc htlc.HTLC) error {
+    return h.record("rescued", htlc)
+}
+
+func (s *KeeperTestSuite) TestHooks_FireOnLifecycle() {
+    hooks := &recordingHooks{}
+    s.keeper.SetHooks(hooks)
+
+    secret := []byte("secret")
+    claimed := s.CreateHTLC(secret, 2*time.Hour)
+    refunded := s.CreateHTLC([]byte("refunded secret"), 2*time.Hour)
+    rescued := s.CreateHTLC([]byte("rescued secret"), 2*time.Hour)
+    s.AdvanceTime(time.Second)
+    tree := testutil.BuildMerkleTree(testutil.NewMerkleSecrets(2))
+    partial := s.CreatePartialFill(tree, 2*time.Hour)
+
+    s.AdvanceTime(time.Minute)
+    s.Require().NoError(s.claim(claimed.ID, s.receiver, secret, nil))
+    for leaf := range tree.Secrets {
+        s.Require().NoError(s.claim(partial.ID, s.receiver, tree.Secrets[leaf], tree.Proofs[leaf]))
+    }
+    s.SetBlockTime(refunded.TimeLock)
+    s.Require().NoError(s.refund(refunded.ID, s.sender))
+    s.SetBlockTime(rescued.TimeLock.Add(htlc.RescueDelay))
+    s.Require().NoError(s.rescueHTLC(s.authority, rescued.ID))
+
+    s.Require().Equal([]string{
+        "created " + claimed.ID,
+        "created " + refunded.ID,
+        "created " + rescued.ID,
+        "claimed " + claimed.ID,
+        "partial_fill " + partial.ID,
+        // The last fill claims the HTLC
+        "partial_fill " + partial.ID,
+        "claimed " + partial.ID,
+        "refunded " + refunded.ID,
+        "rescued " + rescued.ID,
+    }, hooks.calls)
+}
+
+func (s *KeeperTestSuite) TestHooks_ErrorAbortsMessage() {
+    first, failing, last := &recordingHooks{}, &recordingHooks{failOn: "claimed"}, &recordingHooks{}
+    s.keeper.SetHooks(htlc.NewMultiHTLCHooks(first, failing, last))
+
+    secret := []byte("secret")
+    created := s.CreateHTLC(secret, 2*time.Hour)
+    s.AdvanceTime(time.Minute)
+
+    // The tx is reverted like baseapp does when the message fails
+    txCtx, _ := s.ctx.CacheContext()
+    err := s.keeper.ClaimHTLC(txCtx, htlc.MsgClaimHTLC{Claimer: s.receiver, ID: created.ID, Secret: secret})
+    s.Require().ErrorIs(err, errHookFailed)
+
+    // Hooks after the failing one are skipped
+    s.Require().Equal([]string{"created " + created.ID, "claimed " + created.ID}, first.calls)
+    s.Require().Equal([]string{"created " + created.ID}, last.calls)
+
+    stored, err := s.keeper.GetHTLC(s.ctx, created.ID)
+    s.Require().NoError(err)
+    s.Require().False(stored.Claimed)
+}
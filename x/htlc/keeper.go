@@ -3,6 +3,7 @@ package htlc
 
 import (
     "bytes"
+    "fmt"
     "time"
 
     sdk "github.com/cosmos/cosmos-sdk/types"
@@ -12,26 +13,45 @@ import (
 )
 
 type Keeper struct {
-    storeKey sdk.StoreKey
-    cdc      codec.BinaryCodec
-}
+    storeKey       sdk.StoreKey
+    cdc            codec.BinaryCodec
+    bankKeeper     BankKeeper
+    feegrantKeeper FeegrantKeeper // optional, nil disables fee-granted claims
+    hooks          HTLCHooks
+
+    // feegrantMsgServer is optional, nil disables fee-granted claims
+    feegrantMsgServer FeegrantMsgServer
+
+    // lightClientKeeper is optional, nil leaves HTLCs with EscrowTerms unclaimable
+    lightClientKeeper LightClientKeeper
 
-type HTLC struct {
-    ID          string         // unique identifier
-    Sender      sdk.AccAddress // sender address
-    Receiver    sdk.AccAddress // receiver address
-    Amount      sdk.Coins      // locked tokens
-    HashLock    []byte         // hash of the secret
-    TimeLock    time.Time      // expiration time
-    Claimed     bool           // whether claimed
-    Refunded    bool           // whether refunded
+    // authority is the address allowed to execute governance-gated messages,
+    // usually the x/gov module account
+    authority string
 }
 
-func NewKeeper(cdc codec.BinaryCodec, storeKey sdk.StoreKey) Keeper {
+func NewKeeper(cdc codec.BinaryCodec, storeKey sdk.StoreKey, bankKeeper BankKeeper, feegrantKeeper FeegrantKeeper, authority string) Keeper {
     return Keeper{
-        storeKey: storeKey,
-        cdc:      cdc,
+        storeKey:       storeKey,
+        cdc:            cdc,
+        bankKeeper:     bankKeeper,
+        feegrantKeeper: feegrantKeeper,
+        authority:      authority,
+    }
+}
+
+// SetHooks sets the HTLC lifecycle hooks. It can only be called once.
+func (k *Keeper) SetHooks(hooks HTLCHooks) *Keeper {
+    if k.hooks != nil {
+        panic("cannot set htlc hooks twice")
     }
+    k.hooks = hooks
+    return k
+}
+
+// GetAuthority returns the address allowed to execute governance-gated messages
+func (k Keeper) GetAuthority() string {
+    return k.authority
 }
 
 // Store key prefix for HTLCs
@@ -41,12 +61,46 @@ func (k Keeper) getHTLCStore(ctx sdk.Context) prefix.Store {
     return prefix.NewStore(ctx.KVStore(k.storeKey), HTLCKeyPrefix)
 }
 
-func (k Keeper) CreateHTLC(ctx sdk.Context, msg MsgCreateHTLC) error {
+// GetHTLC returns the HTLC stored under id
+func (k Keeper) GetHTLC(ctx sdk.Context, id string) (HTLC, error) {
+    var htlc HTLC
+    bz := k.getHTLCStore(ctx).Get([]byte(id))
+    if bz == nil {
+        return htlc, sdkerrors.Wrap(ErrHTLCNotFound, id)
+    }
+    err := k.cdc.Unmarshal(bz, &htlc)
+    return htlc, err
+}
+
+// CreateHTLC locks msg.Amount and returns the ID of the new HTLC
+func (k Keeper) CreateHTLC(ctx sdk.Context, msg MsgCreateHTLC) (string, error) {
+    id := k.uniqueHTLCID(ctx, HTLCID(msg.Sender, ctx.BlockTime()))
+    return id, k.createHTLC(ctx, msg, id)
+}
+
+// uniqueHTLCID returns id, or id with the smallest ".<n>" suffix no stored
+// HTLC uses yet. IDs have second precision, so HTLCs one sender creates in
+// the same block, e.g. in separate txs, would otherwise collide.
+func (k Keeper) uniqueHTLCID(ctx sdk.Context, id string) string {
+    store := k.getHTLCStore(ctx)
+    unique := id
+    for n := 1; store.Has([]byte(unique)); n++ {
+        unique = fmt.Sprintf("%s.%d", id, n)
+    }
+    return unique
+}
+
+func (k Keeper) createHTLC(ctx sdk.Context, msg MsgCreateHTLC, id string) error {
     store := k.getHTLCStore(ctx)
 
-    id := msg.Sender.String() + "-" + ctx.BlockTime().String()
     if store.Has([]byte(id)) {
-        return sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "HTLC already exists")
+        return sdkerrors.Wrap(ErrHTLCExists, id)
+    }
+    if err := k.GetParams(ctx).ValidateCreate(msg, ctx.BlockTime()); err != nil {
+        return err
+    }
+    if err := k.checkUniqueHashLock(ctx, msg); err != nil {
+        return err
     }
 
     htlc := HTLC{
@@ -60,141 +114,207 @@ func (k Keeper) CreateHTLC(ctx sdk.Context, msg MsgCreateHTLC) error {
         Refunded:      false,
         ExternalChain: msg.ExternalChain,
         ExternalID:    msg.ExternalID,
+        EscrowTerms:   msg.EscrowTerms,
+    }
+    if msg.EscrowImmutables != nil {
+        htlc.EscrowHashLock = msg.EscrowImmutables.HashLock
+    }
+    if msg.Parts > 0 {
+        htlc.MerkleRoot = msg.HashLock
+        htlc.Parts = msg.Parts
     }
 
     // Securely lock tokens by sending from sender to module account
     if err := k.bankKeeper.SendCoinsFromAccountToModule(ctx, msg.Sender, "htlc", msg.Amount); err != nil {
         return err
     }
+    if err := k.lockCoins(ctx, msg.Amount); err != nil {
+        return err
+    }
+
+    if len(msg.FeeAllowance) > 0 {
+        if err := k.grantClaimAllowance(ctx, htlc, msg.FeeAllowance); err != nil {
+            return err
+        }
+        htlc.FeeGranted = true
+    }
 
     // Additional handling for IBC tokens could be added here if needed
 
-    bz, err := k.cdc.Marshal(&htlc)
-    if err != nil {
+    if err := k.setHTLC(ctx, htlc); err != nil {
         return err
     }
+    k.emitStatusChanged(ctx, htlc)
 
-    store.Set([]byte(id), bz)
+    if k.hooks != nil {
+        return k.hooks.AfterHTLCCreated(ctx, htlc)
+    }
     return nil
 }
 
 func (k Keeper) ClaimHTLC(ctx sdk.Context, msg MsgClaimHTLC) error {
-    store := k.getHTLCStore(ctx)
-    bz := store.Get([]byte(msg.ID))
-    if bz == nil {
-        return sdkerrors.Wrap(sdkerrors.ErrUnknownRequest, "HTLC not found")
-    }
+    return k.claimHTLC(ctx, msg, nil, nil)
+}
 
-    var htlc HTLC
-    if err := k.cdc.Unmarshal(bz, &htlc); err != nil {
+// claimHTLC releases the HTLC amount. When relayerFee is set it is paid to
+// relayer out of the released amount and the rest goes to the recipient.
+func (k Keeper) claimHTLC(ctx sdk.Context, msg MsgClaimHTLC, relayer sdk.AccAddress, relayerFee sdk.Coins) error {
+    htlc, err := k.GetHTLC(ctx, msg.ID)
+    if err != nil {
         return err
     }
 
-    if htlc.Claimed {
-        return sdkerrors.Wrap(sdkerrors.ErrUnauthorized, "HTLC already claimed")
+    if err := k.checkTransition(ctx, htlc, ActionClaim, msg.Claimer); err != nil {
+        return err
+    }
+    // Outside the receiver, a public withdrawer can't redirect the coins
+    if !msg.Target.Empty() && !msg.Claimer.Equals(htlc.Receiver) {
+        return sdkerrors.Wrap(ErrNotReceiver, "only the receiver can set a target")
+    }
+    if err := checkEscrowTerms(htlc, msg.Secret); err != nil {
+        return err
     }
-    if htlc.Refunded {
-        return sdkerrors.Wrap(sdkerrors.ErrUnauthorized, "HTLC already refunded")
+    if err := checkEscrowHashLock(htlc, msg.Secret); err != nil {
+        return err
     }
 
+    // A partial fill releases its share only, the rest stays locked for later fills
+    payout := htlc.NextFill()
     // Verify secret with Merkle proof if MerkleRoot is set (partial fill)
     if len(htlc.MerkleRoot) > 0 {
+        // Charge for the whole submitted proof, even the part past MaxMerkleProofDepth
+        ctx.GasMeter().ConsumeGas(GasPerProofNode*uint64(len(msg.MerkleProof)), "htlc merkle proof")
         leaf := sdk.Sha256(msg.Secret)
         if !VerifyMerkleProof(leaf, msg.MerkleProof, htlc.MerkleRoot) {
-            return sdkerrors.Wrap(sdkerrors.ErrUnauthorized, "Invalid Merkle proof")
+            return ErrInvalidProof
         }
-        secretStr := string(msg.Secret)
-        if htlc.UsedSecrets[secretStr] {
-            return sdkerrors.Wrap(sdkerrors.ErrUnauthorized, "Secret already used")
+        if k.isSecretUsed(ctx, htlc.ID, leaf) {
+            return ErrSecretReused
         }
-        htlc.UsedSecrets[secretStr] = true
+        ctx.GasMeter().ConsumeGas(GasPerUsedSecret, "htlc used secret")
+        k.setSecretUsed(ctx, htlc.ID, leaf)
+        htlc.FillCount++
     } else {
         // Single secret verification
         if !bytes.Equal(htlc.HashLock, sdk.Sha256(msg.Secret)) {
-            return sdkerrors.Wrap(sdkerrors.ErrUnauthorized, "Invalid secret")
+            return ErrInvalidSecret
         }
     }
 
-    if ctx.BlockTime().After(htlc.TimeLock) {
-        return sdkerrors.Wrap(sdkerrors.ErrUnauthorized, "HTLC expired")
+    // Transfer tokens from module account to receiver, or to the target
+    // the receiver asked for (mirrors EscrowSrc.withdrawTo)
+    recipient := htlc.Receiver
+    if !msg.Target.Empty() {
+        recipient = msg.Target
     }
-    if !msg.Claimer.Equals(htlc.Receiver) {
-        return sdkerrors.Wrap(sdkerrors.ErrUnauthorized, "Not receiver")
+    if err := k.unlockCoins(ctx, payout); err != nil {
+        return err
     }
-
-    // Transfer tokens from module account to receiver
-    if err := k.bankKeeper.SendCoinsFromModuleToAccount(ctx, "htlc", htlc.Receiver, htlc.Amount); err != nil {
+    if !relayerFee.Empty() {
+        var hasNeg bool
+        payout, hasNeg = payout.SafeSub(relayerFee...)
+        if hasNeg {
+            return sdkerrors.Wrap(sdkerrors.ErrInsufficientFunds, "relayer fee exceeds the released amount")
+        }
+        if err := k.bankKeeper.SendCoinsFromModuleToAccount(ctx, "htlc", relayer, relayerFee); err != nil {
+            return err
+        }
+    }
+    if err := k.bankKeeper.SendCoinsFromModuleToAccount(ctx, "htlc", recipient, payout); err != nil {
         return err
     }
 
     // If all secrets used or single secret, mark claimed
     if len(htlc.MerkleRoot) == 0 || allSecretsUsed(htlc) {
         htlc.Claimed = true
+        if err := k.revokeClaimAllowance(ctx, &htlc); err != nil {
+            return err
+        }
     }
 
-    bz, err := k.cdc.Marshal(&htlc)
-    if err != nil {
+    if err := k.setHTLC(ctx, htlc); err != nil {
+        return err
+    }
+    if err := k.setRevealedSecret(ctx, htlc.ID, msg.Secret); err != nil {
         return err
     }
-    store.Set([]byte(msg.ID), bz)
+    k.emitStatusChanged(ctx, htlc)
+
+    if k.hooks != nil {
+        if len(htlc.MerkleRoot) > 0 {
+            if err := k.hooks.AfterPartialFill(ctx, htlc, msg.Secret); err != nil {
+                return err
+            }
+        }
+        if htlc.Claimed {
+            return k.hooks.AfterHTLCClaimed(ctx, htlc, msg.Secret)
+        }
+    }
     return nil
 }
 
-// VerifyMerkleProof verifies a Merkle proof for a leaf and root
+// VerifyMerkleProof verifies a Merkle proof for a leaf and root. Leaf, root
+// and every proof node must be sha256 sized, and none of them is written to.
 func VerifyMerkleProof(leaf []byte, proof [][]byte, root []byte) bool {
+    if len(leaf) != HashLockLength || len(root) != HashLockLength || len(proof) > MaxMerkleProofDepth {
+        return false
+    }
+
     computedHash := leaf
+    // Hash from a scratch buffer, appending to leaf or p would write into
+    // the caller's backing arrays whenever they have spare capacity
+    pair := make([]byte, 2*HashLockLength)
     for _, p := range proof {
+        if len(p) != HashLockLength {
+            return false
+        }
         if bytes.Compare(computedHash, p) < 0 {
-            computedHash = sdk.Sha256(append(computedHash, p...))
+            copy(pair, computedHash)
+            copy(pair[HashLockLength:], p)
         } else {
-            computedHash = sdk.Sha256(append(p, computedHash...))
+            copy(pair, p)
+            copy(pair[HashLockLength:], computedHash)
         }
+        computedHash = sdk.Sha256(pair)
     }
     return bytes.Equal(computedHash, root)
 }
 
-// allSecretsUsed checks if all secrets have been used (placeholder)
+// allSecretsUsed reports whether every part of a partial-fill HTLC was
+// filled. Records without Parts count as a single part.
 func allSecretsUsed(htlc HTLC) bool {
-    // In a real implementation, track total secrets count and compare with usedSecrets
-    // For now, return false to allow multiple partial fills
-    return false
+    return htlc.FillCount >= htlc.Parts
 }
 
 func (k Keeper) RefundHTLC(ctx sdk.Context, msg MsgRefundHTLC) error {
-    store := k.getHTLCStore(ctx)
-    bz := store.Get([]byte(msg.ID))
-    if bz == nil {
-        return sdkerrors.Wrap(sdkerrors.ErrUnknownRequest, "HTLC not found")
+    htlc, err := k.GetHTLC(ctx, msg.ID)
+    if err != nil {
+        return err
     }
 
-    var htlc HTLC
-    if err := k.cdc.Unmarshal(bz, &htlc); err != nil {
+    if err := k.checkTransition(ctx, htlc, ActionRefund, msg.Sender); err != nil {
         return err
     }
 
-    if htlc.Claimed {
-        return sdkerrors.Wrap(sdkerrors.ErrUnauthorized, "HTLC already claimed")
-    }
-    if htlc.Refunded {
-        return sdkerrors.Wrap(sdkerrors.ErrUnauthorized, "HTLC already refunded")
-    }
-    if ctx.BlockTime().Before(htlc.TimeLock) {
-        return sdkerrors.Wrap(sdkerrors.ErrUnauthorized, "HTLC not expired")
-    }
-    if !msg.Sender.Equals(htlc.Sender) {
-        return sdkerrors.Wrap(sdkerrors.ErrUnauthorized, "Not sender")
+    // Transfer what no fill released from module account back to sender
+    if err := k.bankKeeper.SendCoinsFromModuleToAccount(ctx, "htlc", htlc.Sender, htlc.Remaining()); err != nil {
+        return err
     }
-
-    // Transfer tokens from module account back to sender
-    if err := k.bankKeeper.SendCoinsFromModuleToAccount(ctx, "htlc", htlc.Sender, htlc.Amount); err != nil {
+    if err := k.unlockCoins(ctx, htlc.Remaining()); err != nil {
         return err
     }
 
     htlc.Refunded = true
-    bz, err := k.cdc.Marshal(&htlc)
-    if err != nil {
+    if err := k.revokeClaimAllowance(ctx, &htlc); err != nil {
         return err
     }
-    store.Set([]byte(msg.ID), bz)
+    if err := k.setHTLC(ctx, htlc); err != nil {
+        return err
+    }
+    k.emitStatusChanged(ctx, htlc)
+
+    if k.hooks != nil {
+        return k.hooks.AfterHTLCRefunded(ctx, htlc)
+    }
     return nil
 }
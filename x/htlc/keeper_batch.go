@@ -0,0 +1,77 @@
+// x/htlc/keeper_batch.go
+package htlc
+
+import (
+    "fmt"
+    "strconv"
+
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// BatchCreate creates every HTLC in the batch. IDs are derived from the
+// sender, block time and the item index so items in one batch don't collide,
+// and made unique against batches earlier in the block.
+func (k Keeper) BatchCreate(ctx sdk.Context, msg MsgBatchCreate) ([]BatchItemResult, error) {
+    return k.runBatch(ctx, len(msg.Creates), msg.BestEffort, func(ctx sdk.Context, i int) (string, error) {
+        create := msg.Creates[i]
+        id := k.uniqueHTLCID(ctx, fmt.Sprintf("%s-%d", HTLCID(create.Sender, ctx.BlockTime()), i))
+        return id, k.createHTLC(ctx, create, id)
+    })
+}
+
+// BatchClaim claims every HTLC in the batch
+func (k Keeper) BatchClaim(ctx sdk.Context, msg MsgBatchClaim) ([]BatchItemResult, error) {
+    return k.runBatch(ctx, len(msg.Claims), msg.BestEffort, func(ctx sdk.Context, i int) (string, error) {
+        claim := msg.Claims[i]
+        return claim.ID, k.ClaimHTLC(ctx, claim)
+    })
+}
+
+// BatchRefund refunds every HTLC in the batch
+func (k Keeper) BatchRefund(ctx sdk.Context, msg MsgBatchRefund) ([]BatchItemResult, error) {
+    return k.runBatch(ctx, len(msg.Refunds), msg.BestEffort, func(ctx sdk.Context, i int) (string, error) {
+        refund := msg.Refunds[i]
+        return refund.ID, k.RefundHTLC(ctx, refund)
+    })
+}
+
+// runBatch executes n operations. In atomic mode the first failure aborts the
+// batch and the tx is reverted by the caller. In best-effort mode each item runs
+// in its own cache context, which is only written back when the item succeeds.
+func (k Keeper) runBatch(ctx sdk.Context, n int, bestEffort bool, op func(sdk.Context, int) (string, error)) ([]BatchItemResult, error) {
+    results := make([]BatchItemResult, 0, n)
+    for i := 0; i < n; i++ {
+        if !bestEffort {
+            id, err := op(ctx, i)
+            if err != nil {
+                return nil, sdkerrors.Wrapf(err, "batch item %d (%s)", i, id)
+            }
+            results = append(results, BatchItemResult{Index: uint32(i), ID: id, Success: true})
+            continue
+        }
+
+        cacheCtx, write := ctx.CacheContext()
+        cacheCtx = cacheCtx.WithEventManager(sdk.NewEventManager())
+        id, err := op(cacheCtx, i)
+        result := BatchItemResult{Index: uint32(i), ID: id, Success: err == nil}
+        if err != nil {
+            result.Error = err.Error()
+        } else {
+            write()
+            ctx.EventManager().EmitEvents(cacheCtx.EventManager().Events())
+        }
+        results = append(results, result)
+    }
+
+    for _, result := range results {
+        ctx.EventManager().EmitEvent(sdk.NewEvent(
+            EventTypeBatchItem,
+            sdk.NewAttribute(AttributeKeyIndex, strconv.FormatUint(uint64(result.Index), 10)),
+            sdk.NewAttribute(AttributeKeyID, result.ID),
+            sdk.NewAttribute(AttributeKeySuccess, strconv.FormatBool(result.Success)),
+            sdk.NewAttribute(AttributeKeyError, result.Error),
+        ))
+    }
+    return results, nil
+}
@@ -0,0 +1,85 @@
+// x/htlc/keeper_escrow_proof.go
+package htlc
+
+import (
+    "bytes"
+    "encoding/hex"
+    "strconv"
+
+    "github.com/ethereum/go-ethereum/common"
+    "github.com/ethereum/go-ethereum/crypto"
+
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// SetLightClientKeeper sets the light client escrow proofs are verified
+// against. It can only be called once. Without it HTLCs with EscrowTerms
+// can't be verified, and so can't be claimed.
+func (k *Keeper) SetLightClientKeeper(lightClientKeeper LightClientKeeper) *Keeper {
+    if k.lightClientKeeper != nil {
+        panic("cannot set htlc light client keeper twice")
+    }
+    k.lightClientKeeper = lightClientKeeper
+    return k
+}
+
+// VerifyEscrow checks msg's proofs against the light client header of the
+// HTLC's external chain and marks the HTLC's escrow as verified
+func (k Keeper) VerifyEscrow(ctx sdk.Context, msg MsgVerifyEscrow) error {
+    htlc, err := k.GetHTLC(ctx, msg.ID)
+    if err != nil {
+        return err
+    }
+    if htlc.EscrowTerms == nil {
+        return sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "htlc has no escrow terms")
+    }
+    if htlc.EscrowVerified {
+        return sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "escrow already verified")
+    }
+    if !htlc.IsOpen() {
+        return sdkerrors.Wrapf(ErrInvalidTransition, "htlc is %s", ComputeStatus(htlc, k.GetParams(ctx), ctx.BlockTime()))
+    }
+    if k.lightClientKeeper == nil {
+        return sdkerrors.Wrap(ErrHeaderNotFound, "no light client configured")
+    }
+
+    // Charge for the proofs before walking them
+    ctx.GasMeter().ConsumeGas(GasPerProofNode*uint64(len(msg.ReceiptProof)+len(msg.AccountProof)), "htlc escrow proof")
+    header, found := k.lightClientKeeper.GetEthHeader(ctx, htlc.ExternalChain, msg.BlockHash)
+    if !found {
+        return sdkerrors.Wrapf(ErrHeaderNotFound, "%s block %x", htlc.ExternalChain, msg.BlockHash)
+    }
+    escrow := common.HexToAddress(htlc.ExternalID)
+    if err := verifyEscrowDeployment(header, *htlc.EscrowTerms, escrow, msg); err != nil {
+        return sdkerrors.Wrap(ErrInvalidEscrowProof, err.Error())
+    }
+
+    htlc.EscrowVerified = true
+    if err := k.setHTLC(ctx, htlc); err != nil {
+        return err
+    }
+    ctx.EventManager().EmitEvent(sdk.NewEvent(
+        EventTypeEscrowVerified,
+        sdk.NewAttribute(AttributeKeyID, htlc.ID),
+        sdk.NewAttribute(AttributeKeyEscrow, escrow.Hex()),
+        sdk.NewAttribute(AttributeKeyBlockHash, hex.EncodeToString(msg.BlockHash)),
+        sdk.NewAttribute(AttributeKeyBlockNumber, strconv.FormatUint(header.Number, 10)),
+    ))
+    return nil
+}
+
+// checkEscrowTerms gates claims of HTLCs with EscrowTerms: the escrow must be
+// verified and the secret must also open it, so both sides share the secret
+func checkEscrowTerms(htlc HTLC, secret []byte) error {
+    if htlc.EscrowTerms == nil {
+        return nil
+    }
+    if !htlc.EscrowVerified {
+        return sdkerrors.Wrap(ErrEscrowNotVerified, htlc.ExternalID)
+    }
+    if !bytes.Equal(crypto.Keccak256(secret), htlc.EscrowTerms.HashLock) {
+        return sdkerrors.Wrap(ErrInvalidSecret, "secret does not match the escrow hashlock")
+    }
+    return nil
+}
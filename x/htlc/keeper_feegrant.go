@@ -0,0 +1,83 @@
+// x/htlc/keeper_feegrant.go
+package htlc
+
+import (
+    "cosmossdk.io/x/feegrant"
+
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    "github.com/cosmos/cosmos-sdk/types/address"
+    sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// Store key prefix for fee grants opened on behalf of HTLCs, keyed by
+// sender and receiver. Only one fee-granted HTLC may be open per pair since
+// feegrant allows a single allowance per granter/grantee.
+var FeeGrantKeyPrefix = []byte{0x02}
+
+// feeGrantKey length-prefixes both addresses, so a sender/receiver pair
+// can't alias another pair whose addresses split the bytes differently
+func feeGrantKey(sender, receiver sdk.AccAddress) []byte {
+    key := append([]byte{}, FeeGrantKeyPrefix...)
+    key = append(key, address.MustLengthPrefix(sender)...)
+    return append(key, address.MustLengthPrefix(receiver)...)
+}
+
+// SetFeegrantMsgServer sets the feegrant Msg service allowances of
+// fee-granted claims are revoked through. It can only be called once.
+// Without it fee-granted claims are disabled.
+func (k *Keeper) SetFeegrantMsgServer(msgServer FeegrantMsgServer) *Keeper {
+    if k.feegrantMsgServer != nil {
+        panic("cannot set htlc feegrant msg server twice")
+    }
+    k.feegrantMsgServer = msgServer
+    return k
+}
+
+// grantClaimAllowance grants the receiver an allowance paid by the sender,
+// limited to htlc claims and expiring at the HTLC's TimeLock
+func (k Keeper) grantClaimAllowance(ctx sdk.Context, htlc HTLC, spendLimit sdk.Coins) error {
+    if k.feegrantKeeper == nil || k.feegrantMsgServer == nil {
+        return sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "fee-granted claims are not enabled")
+    }
+
+    store := ctx.KVStore(k.storeKey)
+    key := feeGrantKey(htlc.Sender, htlc.Receiver)
+    if store.Has(key) {
+        return sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "a fee-granted HTLC is already open for this receiver")
+    }
+
+    expiration := htlc.TimeLock
+    basic := &feegrant.BasicAllowance{
+        SpendLimit: spendLimit,
+        Expiration: &expiration,
+    }
+    allowance, err := feegrant.NewAllowedMsgAllowance(basic, []string{TypeURLMsgClaimHTLC})
+    if err != nil {
+        return err
+    }
+    if err := k.feegrantKeeper.GrantAllowance(ctx, htlc.Sender, htlc.Receiver, allowance); err != nil {
+        return err
+    }
+
+    store.Set(key, []byte(htlc.ID))
+    return nil
+}
+
+// revokeClaimAllowance removes the allowance granted for htlc, if any. An
+// allowance that already expired or was used up is no longer held by
+// feegrant, so revocation errors are not fatal.
+func (k Keeper) revokeClaimAllowance(ctx sdk.Context, htlc *HTLC) error {
+    if !htlc.FeeGranted || k.feegrantMsgServer == nil {
+        return nil
+    }
+
+    store := ctx.KVStore(k.storeKey)
+    store.Delete(feeGrantKey(htlc.Sender, htlc.Receiver))
+    htlc.FeeGranted = false
+
+    msg := &feegrant.MsgRevokeAllowance{Granter: htlc.Sender.String(), Grantee: htlc.Receiver.String()}
+    if _, err := k.feegrantMsgServer.RevokeAllowance(ctx, msg); err != nil {
+        ctx.Logger().Info("htlc fee allowance already gone", "id", htlc.ID, "err", err)
+    }
+    return nil
+}
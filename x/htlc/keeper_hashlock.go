@@ -0,0 +1,53 @@
+// x/htlc/keeper_hashlock.go
+package htlc
+
+import (
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// GetHashLockUsage reports the open HTLCs using hashLock and whether its
+// secret was already revealed by a claim
+func (k Keeper) GetHashLockUsage(ctx sdk.Context, hashLock []byte) (HashLockUsage, error) {
+    usage := HashLockUsage{HashLock: hashLock, Revealed: k.HasRevealedSecret(ctx, hashLock)}
+    htlcs, err := k.GetHTLCsByHashLock(ctx, hashLock)
+    if err != nil {
+        return usage, err
+    }
+
+    for _, htlc := range htlcs {
+        if htlc.IsOpen() {
+            usage.OpenIDs = append(usage.OpenIDs, htlc.ID)
+        }
+        if htlc.Claimed || htlc.FillCount > 0 {
+            usage.Revealed = true
+        }
+    }
+
+    switch {
+    case usage.Revealed:
+        usage.Warning = "secret for this hashlock was already revealed on-chain"
+    case len(usage.OpenIDs) > 0:
+        usage.Warning = "hashlock is already used by open HTLCs"
+    }
+    return usage, nil
+}
+
+// checkUniqueHashLock rejects msg when EnforceUniqueHashLock is set and an
+// open HTLC between the same sender and receiver uses the same hashlock
+func (k Keeper) checkUniqueHashLock(ctx sdk.Context, msg MsgCreateHTLC) error {
+    if !k.GetParams(ctx).EnforceUniqueHashLock {
+        return nil
+    }
+
+    htlcs, err := k.GetHTLCsByHashLock(ctx, msg.HashLock)
+    if err != nil {
+        return err
+    }
+    for _, htlc := range htlcs {
+        if htlc.IsOpen() && htlc.Sender.Equals(msg.Sender) && htlc.Receiver.Equals(msg.Receiver) {
+            return sdkerrors.Wrap(ErrHashLockInUse, htlc.ID)
+        }
+    }
+    return nil
+}
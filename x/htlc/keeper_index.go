@@ -0,0 +1,124 @@
+// x/htlc/keeper_index.go
+package htlc
+
+import (
+    "github.com/cosmos/cosmos-sdk/store/prefix"
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    "github.com/cosmos/cosmos-sdk/types/address"
+    sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// Store key prefixes for the secondary indexes. Each index entry maps
+// <prefix><length-prefixed key><HTLC ID> to an empty value.
+var (
+    SenderIndexPrefix     = []byte{0x03}
+    ReceiverIndexPrefix   = []byte{0x04}
+    HashLockIndexPrefix   = []byte{0x05}
+    ExternalIDIndexPrefix = []byte{0x06}
+)
+
+// externalIndexKey fails for a chain or ID longer than MaxExternalFieldLength
+func externalIndexKey(chain, externalID string) ([]byte, error) {
+    chainKey, err := address.LengthPrefix([]byte(chain))
+    if err != nil {
+        return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "external chain too long")
+    }
+    idKey, err := address.LengthPrefix([]byte(externalID))
+    if err != nil {
+        return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "external ID too long")
+    }
+    return append(chainKey, idKey...), nil
+}
+
+// setHTLC writes htlc and its index entries, charging GasPerRecordByte for
+// the record. Indexed fields never change after creation, so rewriting the
+// entries on every update keeps them consistent.
+func (k Keeper) setHTLC(ctx sdk.Context, htlc HTLC) error {
+    var externalKey []byte
+    if htlc.ExternalID != "" {
+        var err error
+        if externalKey, err = externalIndexKey(htlc.ExternalChain, htlc.ExternalID); err != nil {
+            return err
+        }
+    }
+
+    bz, err := k.cdc.Marshal(&htlc)
+    if err != nil {
+        return err
+    }
+    ctx.GasMeter().ConsumeGas(GasPerRecordByte*uint64(len(bz)), "htlc record write")
+    k.getHTLCStore(ctx).Set([]byte(htlc.ID), bz)
+
+    id := []byte(htlc.ID)
+    store := ctx.KVStore(k.storeKey)
+    prefix.NewStore(store, SenderIndexPrefix).Set(append(address.MustLengthPrefix(htlc.Sender), id...), []byte{})
+    prefix.NewStore(store, ReceiverIndexPrefix).Set(append(address.MustLengthPrefix(htlc.Receiver), id...), []byte{})
+    prefix.NewStore(store, HashLockIndexPrefix).Set(append(address.MustLengthPrefix(htlc.HashLock), id...), []byte{})
+    if externalKey != nil {
+        prefix.NewStore(store, ExternalIDIndexPrefix).Set(append(externalKey, id...), []byte{})
+    }
+    return nil
+}
+
+// deleteHTLC removes htlc and its index entries
+func (k Keeper) deleteHTLC(ctx sdk.Context, htlc HTLC) error {
+    var externalKey []byte
+    if htlc.ExternalID != "" {
+        var err error
+        if externalKey, err = externalIndexKey(htlc.ExternalChain, htlc.ExternalID); err != nil {
+            return err
+        }
+    }
+
+    id := []byte(htlc.ID)
+    store := ctx.KVStore(k.storeKey)
+    k.getHTLCStore(ctx).Delete(id)
+    prefix.NewStore(store, SenderIndexPrefix).Delete(append(address.MustLengthPrefix(htlc.Sender), id...))
+    prefix.NewStore(store, ReceiverIndexPrefix).Delete(append(address.MustLengthPrefix(htlc.Receiver), id...))
+    prefix.NewStore(store, HashLockIndexPrefix).Delete(append(address.MustLengthPrefix(htlc.HashLock), id...))
+    if externalKey != nil {
+        prefix.NewStore(store, ExternalIDIndexPrefix).Delete(append(externalKey, id...))
+    }
+    return nil
+}
+
+// getHTLCsByIndex returns the HTLCs whose index entries start with indexPrefix+key
+func (k Keeper) getHTLCsByIndex(ctx sdk.Context, indexPrefix, key []byte) ([]HTLC, error) {
+    indexStore := prefix.NewStore(ctx.KVStore(k.storeKey), append(append([]byte{}, indexPrefix...), key...))
+    iterator := indexStore.Iterator(nil, nil)
+    defer iterator.Close()
+
+    var htlcs []HTLC
+    for ; iterator.Valid(); iterator.Next() {
+        htlc, err := k.GetHTLC(ctx, string(iterator.Key()))
+        if err != nil {
+            return nil, err
+        }
+        htlcs = append(htlcs, htlc)
+    }
+    return htlcs, nil
+}
+
+// GetHTLCsBySender returns the HTLCs created by sender
+func (k Keeper) GetHTLCsBySender(ctx sdk.Context, sender sdk.AccAddress) ([]HTLC, error) {
+    return k.getHTLCsByIndex(ctx, SenderIndexPrefix, address.MustLengthPrefix(sender))
+}
+
+// GetHTLCsByReceiver returns the HTLCs payable to receiver
+func (k Keeper) GetHTLCsByReceiver(ctx sdk.Context, receiver sdk.AccAddress) ([]HTLC, error) {
+    return k.getHTLCsByIndex(ctx, ReceiverIndexPrefix, address.MustLengthPrefix(receiver))
+}
+
+// GetHTLCsByHashLock returns the HTLCs locked with hashLock
+func (k Keeper) GetHTLCsByHashLock(ctx sdk.Context, hashLock []byte) ([]HTLC, error) {
+    return k.getHTLCsByIndex(ctx, HashLockIndexPrefix, address.MustLengthPrefix(hashLock))
+}
+
+// GetHTLCsByExternalID returns the HTLCs mirroring the given HTLC on an external chain
+func (k Keeper) GetHTLCsByExternalID(ctx sdk.Context, chain, externalID string) ([]HTLC, error) {
+    key, err := externalIndexKey(chain, externalID)
+    if err != nil {
+        return nil, err
+    }
+    return k.getHTLCsByIndex(ctx, ExternalIDIndexPrefix, key)
+}
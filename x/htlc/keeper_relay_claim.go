@@ -0,0 +1,58 @@
+// x/htlc/keeper_relay_claim.go
+package htlc
+
+import (
+    "github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// MaxRelayerFeeBps caps the relayer fee of a relayed claim, in basis points of
+// each denom the claim releases
+const MaxRelayerFeeBps = 100
+
+// RelayClaim checks the receiver's signature over the relay terms and claims
+// the HTLC for the receiver, paying the relayer fee out of the released amount
+func (k Keeper) RelayClaim(ctx sdk.Context, msg MsgRelayClaim) error {
+    htlc, err := k.GetHTLC(ctx, msg.ID)
+    if err != nil {
+        return err
+    }
+
+    // Address panics on keys of any other length
+    if len(msg.ReceiverPubKey) != secp256k1.PubKeySize {
+        return sdkerrors.Wrapf(sdkerrors.ErrInvalidPubKey, "receiver public key must be %d bytes", secp256k1.PubKeySize)
+    }
+    pubKey := &secp256k1.PubKey{Key: msg.ReceiverPubKey}
+    if !sdk.AccAddress(pubKey.Address()).Equals(htlc.Receiver) {
+        return sdkerrors.Wrap(sdkerrors.ErrInvalidPubKey, "public key does not match receiver")
+    }
+    signBytes := RelayClaimSignBytes(ctx.ChainID(), msg.ID, msg.Secret, msg.Fee, htlc.FillCount, msg.Relayer)
+    if !pubKey.VerifySignature(signBytes, msg.Signature) {
+        return sdkerrors.Wrap(sdkerrors.ErrUnauthorized, "invalid receiver signature")
+    }
+
+    if err := checkRelayerFee(msg.Fee, htlc.NextFill()); err != nil {
+        return err
+    }
+
+    claim := MsgClaimHTLC{
+        Claimer:     htlc.Receiver,
+        ID:          msg.ID,
+        Secret:      msg.Secret,
+        MerkleProof: msg.MerkleProof,
+    }
+    return k.claimHTLC(ctx, claim, msg.Relayer, msg.Fee)
+}
+
+// checkRelayerFee caps fee against released, the payout of the fill being
+// claimed, so a partial fill can't pay the relayer out of later fills
+func checkRelayerFee(fee, released sdk.Coins) error {
+    for _, coin := range fee {
+        max := released.AmountOf(coin.Denom).MulRaw(MaxRelayerFeeBps).QuoRaw(10000)
+        if coin.Amount.GT(max) {
+            return sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "relayer fee %s exceeds cap of %s%s", coin, max, coin.Denom)
+        }
+    }
+    return nil
+}
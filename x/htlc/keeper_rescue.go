@@ -0,0 +1,192 @@
+// x/htlc/keeper_rescue.go
+package htlc
+
+import (
+    "time"
+
+    "github.com/cosmos/cosmos-sdk/store/prefix"
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+    authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+)
+
+// RescueDelay is how long after its TimeLock an open HTLC can be rescued
+const RescueDelay = 30 * 24 * time.Hour
+
+// LockedBalanceKeyPrefix prefixes the running total of coins open HTLCs
+// still hold, one entry per denom. It is kept up to date wherever coins
+// enter or leave an HTLC, so rescues don't have to walk every record.
+var LockedBalanceKeyPrefix = []byte{0x0C}
+
+func (k Keeper) getLockedBalanceStore(ctx sdk.Context) prefix.Store {
+    return prefix.NewStore(ctx.KVStore(k.storeKey), LockedBalanceKeyPrefix)
+}
+
+// LockedBalance returns the sum of the amounts still held by open HTLCs
+func (k Keeper) LockedBalance(ctx sdk.Context) (sdk.Coins, error) {
+    iterator := k.getLockedBalanceStore(ctx).Iterator(nil, nil)
+    defer iterator.Close()
+
+    locked := sdk.NewCoins()
+    for ; iterator.Valid(); iterator.Next() {
+        var amount sdk.Int
+        if err := amount.Unmarshal(iterator.Value()); err != nil {
+            return nil, err
+        }
+        locked = locked.Add(sdk.NewCoin(string(iterator.Key()), amount))
+    }
+    return locked, nil
+}
+
+// lockCoins adds coins an HTLC took in to the locked total
+func (k Keeper) lockCoins(ctx sdk.Context, coins sdk.Coins) error {
+    store := k.getLockedBalanceStore(ctx)
+    for _, coin := range coins {
+        amount, err := k.lockedAmount(store, coin.Denom)
+        if err != nil {
+            return err
+        }
+        bz, err := amount.Add(coin.Amount).Marshal()
+        if err != nil {
+            return err
+        }
+        store.Set([]byte(coin.Denom), bz)
+    }
+    return nil
+}
+
+// unlockCoins removes coins an HTLC paid out from the locked total
+func (k Keeper) unlockCoins(ctx sdk.Context, coins sdk.Coins) error {
+    store := k.getLockedBalanceStore(ctx)
+    for _, coin := range coins {
+        amount, err := k.lockedAmount(store, coin.Denom)
+        if err != nil {
+            return err
+        }
+        amount = amount.Sub(coin.Amount)
+        switch {
+        case amount.IsNegative():
+            return sdkerrors.Wrapf(sdkerrors.ErrLogic, "unlocking %s exceeds the locked balance", coin)
+        case amount.IsZero():
+            store.Delete([]byte(coin.Denom))
+        default:
+            bz, err := amount.Marshal()
+            if err != nil {
+                return err
+            }
+            store.Set([]byte(coin.Denom), bz)
+        }
+    }
+    return nil
+}
+
+func (k Keeper) lockedAmount(store prefix.Store, denom string) (sdk.Int, error) {
+    bz := store.Get([]byte(denom))
+    if bz == nil {
+        return sdk.ZeroInt(), nil
+    }
+    var amount sdk.Int
+    if err := amount.Unmarshal(bz); err != nil {
+        return sdk.Int{}, err
+    }
+    return amount, nil
+}
+
+// resetLockedBalance rebuilds the locked total from the stored HTLCs
+func (k Keeper) resetLockedBalance(ctx sdk.Context) error {
+    store := k.getLockedBalanceStore(ctx)
+    var denoms [][]byte
+    iterator := store.Iterator(nil, nil)
+    for ; iterator.Valid(); iterator.Next() {
+        denoms = append(denoms, iterator.Key())
+    }
+    iterator.Close()
+    for _, denom := range denoms {
+        store.Delete(denom)
+    }
+
+    locked := sdk.NewCoins()
+    if err := k.IterateHTLCs(ctx, func(htlc HTLC) bool {
+        if htlc.IsOpen() {
+            locked = locked.Add(htlc.Remaining()...)
+        }
+        return false
+    }); err != nil {
+        return err
+    }
+    return k.lockCoins(ctx, locked)
+}
+
+// IterateHTLCs calls cb for every stored HTLC until cb returns true
+func (k Keeper) IterateHTLCs(ctx sdk.Context, cb func(htlc HTLC) (stop bool)) error {
+    iterator := k.getHTLCStore(ctx).Iterator(nil, nil)
+    defer iterator.Close()
+
+    for ; iterator.Valid(); iterator.Next() {
+        var htlc HTLC
+        if err := k.cdc.Unmarshal(iterator.Value(), &htlc); err != nil {
+            return err
+        }
+        if cb(htlc) {
+            break
+        }
+    }
+    return nil
+}
+
+func (k Keeper) RescueFunds(ctx sdk.Context, msg MsgRescueFunds) error {
+    if msg.Authority.String() != k.authority {
+        return sdkerrors.Wrapf(sdkerrors.ErrUnauthorized, "expected %s as authority, got %s", k.authority, msg.Authority)
+    }
+
+    locked, err := k.LockedBalance(ctx)
+    if err != nil {
+        return err
+    }
+    balance := k.bankKeeper.GetAllBalances(ctx, authtypes.NewModuleAddress("htlc"))
+    surplus, hasNeg := balance.SafeSub(locked...)
+    if hasNeg {
+        // The module account holds less than it owes, nothing is stray
+        surplus = sdk.NewCoins()
+    }
+    if !surplus.IsAllGTE(msg.Amount) {
+        return sdkerrors.Wrapf(sdkerrors.ErrInsufficientFunds, "rescue amount %s exceeds surplus %s", msg.Amount, surplus)
+    }
+
+    return k.bankKeeper.SendCoinsFromModuleToAccount(ctx, "htlc", msg.Recipient, msg.Amount)
+}
+
+func (k Keeper) RescueHTLC(ctx sdk.Context, msg MsgRescueHTLC) error {
+    htlc, err := k.GetHTLC(ctx, msg.ID)
+    if err != nil {
+        return err
+    }
+
+    if err := k.checkTransition(ctx, htlc, ActionRescue, msg.Authority); err != nil {
+        return err
+    }
+    if ctx.BlockTime().Before(htlc.TimeLock.Add(RescueDelay)) {
+        return sdkerrors.Wrapf(ErrRescueDelayNotElapsed, "rescuable from %s", htlc.TimeLock.Add(RescueDelay))
+    }
+
+    if err := k.bankKeeper.SendCoinsFromModuleToAccount(ctx, "htlc", msg.Recipient, htlc.Remaining()); err != nil {
+        return err
+    }
+    if err := k.unlockCoins(ctx, htlc.Remaining()); err != nil {
+        return err
+    }
+
+    htlc.Rescued = true
+    if err := k.revokeClaimAllowance(ctx, &htlc); err != nil {
+        return err
+    }
+    if err := k.setHTLC(ctx, htlc); err != nil {
+        return err
+    }
+    k.emitStatusChanged(ctx, htlc)
+
+    if k.hooks != nil {
+        return k.hooks.AfterHTLCRescued(ctx, htlc)
+    }
+    return nil
+}
@@ -0,0 +1,179 @@
+// x/htlc/keeper_route.go
+package htlc
+
+import (
+    "encoding/hex"
+    "strconv"
+    "time"
+
+    "github.com/cosmos/cosmos-sdk/store/prefix"
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// Store key prefix for routes, keyed by route ID
+var RouteKeyPrefix = []byte{0x0B}
+
+// Route statuses, derived from the local hop since the other hops live on
+// chains this module can't observe
+const (
+    RouteStatusPending   = "pending"   // local hop open, secret not revealed
+    RouteStatusRevealed  = "revealed"  // local hop claimed, the secret is public
+    RouteStatusExpired   = "expired"   // local hop expired unclaimed
+    RouteStatusCancelled = "cancelled" // local hop refunded or rescued
+
+    HopStatusPending   = "pending"   // the secret isn't known yet
+    HopStatusClaimable = "claimable" // the secret is public and the hop not expired
+    HopStatusExpired   = "expired"   // the hop's timelock passed
+)
+
+func (k Keeper) getRouteStore(ctx sdk.Context) prefix.Store {
+    return prefix.NewStore(ctx.KVStore(k.storeKey), RouteKeyPrefix)
+}
+
+// GetRoute returns the route stored under id
+func (k Keeper) GetRoute(ctx sdk.Context, id string) (Route, error) {
+    var route Route
+    bz := k.getRouteStore(ctx).Get([]byte(id))
+    if bz == nil {
+        return route, sdkerrors.Wrap(ErrRouteNotFound, id)
+    }
+    err := k.cdc.Unmarshal(bz, &route)
+    return route, err
+}
+
+// CreateRoute validates the route's timelock margins, creates the local hop
+// and emits an htlc_route_hop event per other hop for the parties that must
+// lock them. It returns the route ID.
+func (k Keeper) CreateRoute(ctx sdk.Context, msg MsgCreateRoute) (string, error) {
+    params := k.GetParams(ctx)
+    if err := validateRouteTimeLocks(params, msg, ctx.BlockTime()); err != nil {
+        return "", err
+    }
+
+    var local int
+    for i, hop := range msg.Hops {
+        if hop.IsLocal() {
+            local = i
+            continue
+        }
+        if !containsString(params.ExternalChains, hop.Chain) {
+            return "", sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "hop %d: external chain %s is not registered", i, hop.Chain)
+        }
+    }
+
+    hop := msg.Hops[local]
+    receiver, err := sdk.AccAddressFromBech32(hop.Receiver)
+    if err != nil {
+        return "", err
+    }
+    amount, err := sdk.ParseCoinsNormalized(hop.Amount)
+    if err != nil {
+        return "", err
+    }
+    id := k.uniqueHTLCID(ctx, HTLCID(msg.Sender, ctx.BlockTime()))
+    create := MsgCreateHTLC{
+        Sender:   msg.Sender,
+        Receiver: receiver,
+        Amount:   amount,
+        HashLock: msg.HashLock,
+        TimeLock: hop.TimeLock,
+    }
+    if err := k.createHTLC(ctx, create, id); err != nil {
+        return "", err
+    }
+
+    route := Route{
+        ID:       id,
+        Sender:   msg.Sender,
+        HashLock: msg.HashLock,
+        Hops:     msg.Hops,
+        LocalHop: uint32(local),
+    }
+    bz, err := k.cdc.Marshal(&route)
+    if err != nil {
+        return "", err
+    }
+    k.getRouteStore(ctx).Set([]byte(id), bz)
+
+    for i, hop := range msg.Hops {
+        if i == local {
+            continue
+        }
+        hashLock := hop.HashLock
+        if len(hashLock) == 0 {
+            hashLock = msg.HashLock
+        }
+        ctx.EventManager().EmitEvent(sdk.NewEvent(
+            EventTypeRouteHop,
+            sdk.NewAttribute(AttributeKeyRouteID, id),
+            sdk.NewAttribute(AttributeKeyIndex, strconv.Itoa(i)),
+            sdk.NewAttribute(AttributeKeyChain, hop.Chain),
+            sdk.NewAttribute(AttributeKeySender, hop.Sender),
+            sdk.NewAttribute(AttributeKeyReceiver, hop.Receiver),
+            sdk.NewAttribute(AttributeKeyAmount, hop.Amount),
+            sdk.NewAttribute(AttributeKeyHashLock, hex.EncodeToString(hashLock)),
+            sdk.NewAttribute(AttributeKeyTimeLock, strconv.FormatUint(hop.TimeLock, 10)),
+        ))
+    }
+    return id, nil
+}
+
+// validateRouteTimeLocks checks that consecutive hops are at least
+// MinRouteTimeLockDelta apart and that all timelocks fall within the window
+// HTLCs may be created with
+func validateRouteTimeLocks(params Params, msg MsgCreateRoute, now time.Time) error {
+    first := time.Unix(int64(msg.Hops[0].TimeLock), 0)
+    if first.After(now.Add(params.MaxTimeLockDuration)) {
+        return sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "first hop timelock must be at most %s in the future", params.MaxTimeLockDuration)
+    }
+    last := time.Unix(int64(msg.Hops[len(msg.Hops)-1].TimeLock), 0)
+    if last.Before(now.Add(params.MinTimeLockDuration)) {
+        return sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "last hop timelock must be at least %s in the future", params.MinTimeLockDuration)
+    }
+    for i := 1; i < len(msg.Hops); i++ {
+        delta := time.Duration(int64(msg.Hops[i-1].TimeLock)-int64(msg.Hops[i].TimeLock)) * time.Second
+        if delta < params.MinRouteTimeLockDelta() {
+            return sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "hops %d and %d timelocks are %s apart, at least %s required", i-1, i, delta, params.MinRouteTimeLockDelta())
+        }
+    }
+    return nil
+}
+
+// RouteStatus returns the status of the route and of each of its hops
+func (k Keeper) RouteStatus(ctx sdk.Context, id string) (QueryRouteResponse, error) {
+    route, err := k.GetRoute(ctx, id)
+    if err != nil {
+        return QueryRouteResponse{}, err
+    }
+    local, err := k.GetHTLC(ctx, route.ID)
+    if err != nil {
+        return QueryRouteResponse{}, err
+    }
+    now := ctx.BlockTime()
+    localStatus := ComputeStatus(local, k.GetParams(ctx), now)
+
+    resp := QueryRouteResponse{ID: route.ID, Status: RouteStatusPending}
+    switch localStatus {
+    case StatusClaimed:
+        resp.Status = RouteStatusRevealed
+    case StatusRefunded, StatusRescued:
+        resp.Status = RouteStatusCancelled
+    case StatusExpired, StatusPublicCancellable:
+        resp.Status = RouteStatusExpired
+    }
+
+    for i, hop := range route.Hops {
+        status := HopStatusPending
+        switch {
+        case uint32(i) == route.LocalHop:
+            status = localStatus.String()
+        case !now.Before(time.Unix(int64(hop.TimeLock), 0)):
+            status = HopStatusExpired
+        case resp.Status == RouteStatusRevealed:
+            status = HopStatusClaimable
+        }
+        resp.Hops = append(resp.Hops, RouteHopStatus{RouteHop: hop, Index: uint32(i), Status: status})
+    }
+    return resp, nil
+}
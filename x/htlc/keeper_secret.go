@@ -0,0 +1,108 @@
+// x/htlc/keeper_secret.go
+package htlc
+
+import (
+    "encoding/binary"
+
+    "github.com/cosmos/cosmos-sdk/store/prefix"
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    "github.com/cosmos/cosmos-sdk/types/address"
+)
+
+// Store key prefixes for the revealed-secret registry and its pruning queue.
+// Secrets are keyed by sha256(secret), which is the hashlock for single-secret
+// HTLCs and the Merkle leaf for partial fills. The queue maps
+// <height><hashlock> to an empty value.
+var (
+    RevealedSecretKeyPrefix   = []byte{0x08}
+    RevealedSecretQueuePrefix = []byte{0x09}
+)
+
+// Store key prefix for the secrets consumed by partial fills, keyed by
+// <length-prefixed HTLC ID><sha256(secret)>
+var UsedSecretKeyPrefix = []byte{0x0A}
+
+func revealedSecretQueueKey(height int64, hashLock []byte) []byte {
+    key := make([]byte, 8, 8+len(hashLock))
+    binary.BigEndian.PutUint64(key, uint64(height))
+    return append(key, hashLock...)
+}
+
+// setRevealedSecret records secret as revealed by the claim of htlcID
+func (k Keeper) setRevealedSecret(ctx sdk.Context, htlcID string, secret []byte) error {
+    revealed := RevealedSecret{
+        HashLock: sdk.Sha256(secret),
+        Secret:   secret,
+        Height:   ctx.BlockHeight(),
+        HTLCID:   htlcID,
+    }
+    bz, err := k.cdc.Marshal(&revealed)
+    if err != nil {
+        return err
+    }
+
+    store := ctx.KVStore(k.storeKey)
+    prefix.NewStore(store, RevealedSecretKeyPrefix).Set(revealed.HashLock, bz)
+    prefix.NewStore(store, RevealedSecretQueuePrefix).Set(revealedSecretQueueKey(revealed.Height, revealed.HashLock), []byte{})
+    return nil
+}
+
+// GetRevealedSecret returns the secret revealed for hashLock
+func (k Keeper) GetRevealedSecret(ctx sdk.Context, hashLock []byte) (RevealedSecret, error) {
+    var revealed RevealedSecret
+    bz := prefix.NewStore(ctx.KVStore(k.storeKey), RevealedSecretKeyPrefix).Get(hashLock)
+    if bz == nil {
+        return revealed, ErrSecretNotRevealed
+    }
+    err := k.cdc.Unmarshal(bz, &revealed)
+    return revealed, err
+}
+
+// HasRevealedSecret reports whether the secret for hashLock is in the registry
+func (k Keeper) HasRevealedSecret(ctx sdk.Context, hashLock []byte) bool {
+    return prefix.NewStore(ctx.KVStore(k.storeKey), RevealedSecretKeyPrefix).Has(hashLock)
+}
+
+// PruneRevealedSecrets removes the secrets revealed more than
+// RevealedSecretRetention blocks ago
+func (k Keeper) PruneRevealedSecrets(ctx sdk.Context) {
+    retention := k.GetParams(ctx).RevealedSecretRetention
+    if retention == 0 || ctx.BlockHeight() <= int64(retention) {
+        return
+    }
+    cutoff := ctx.BlockHeight() - int64(retention)
+
+    store := ctx.KVStore(k.storeKey)
+    secrets := prefix.NewStore(store, RevealedSecretKeyPrefix)
+    queue := prefix.NewStore(store, RevealedSecretQueuePrefix)
+
+    // Collect first, deleting while iterating is not allowed
+    iterator := queue.Iterator(nil, revealedSecretQueueKey(cutoff+1, nil))
+    var keys [][]byte
+    for ; iterator.Valid(); iterator.Next() {
+        keys = append(keys, iterator.Key())
+    }
+    iterator.Close()
+
+    for _, key := range keys {
+        height := int64(binary.BigEndian.Uint64(key[:8]))
+        hashLock := key[8:]
+        // Only drop the secret if it wasn't revealed again at a later height
+        if revealed, err := k.GetRevealedSecret(ctx, hashLock); err == nil && revealed.Height == height {
+            secrets.Delete(hashLock)
+        }
+        queue.Delete(key)
+    }
+}
+
+func usedSecretKey(id string, leaf []byte) []byte {
+    return append(address.MustLengthPrefix([]byte(id)), leaf...)
+}
+
+func (k Keeper) isSecretUsed(ctx sdk.Context, id string, leaf []byte) bool {
+    return prefix.NewStore(ctx.KVStore(k.storeKey), UsedSecretKeyPrefix).Has(usedSecretKey(id, leaf))
+}
+
+func (k Keeper) setSecretUsed(ctx sdk.Context, id string, leaf []byte) {
+    prefix.NewStore(ctx.KVStore(k.storeKey), UsedSecretKeyPrefix).Set(usedSecretKey(id, leaf), []byte{})
+}
@@ -0,0 +1,306 @@
+// x/htlc/keeper_suite_test.go
+package htlc_test
+
+import (
+    "testing"
+    "time"
+
+    "github.com/cosmos/cosmos-sdk/codec"
+    codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+    "github.com/cosmos/cosmos-sdk/store"
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    "github.com/stretchr/testify/suite"
+    "github.com/tendermint/tendermint/libs/log"
+    tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+    dbm "github.com/tendermint/tm-db"
+
+    "github.com/your_repo/x/htlc"
+    "github.com/your_repo/x/htlc/testutil"
+)
+
+// genesisTime is the block time every suite test starts from
+var genesisTime = time.Unix(1_700_000_000, 0).UTC()
+
+// KeeperTestSuite runs keeper tests against a block clock the test controls
+// and a mock bank keeper, so timelock boundaries can be hit to the second
+type KeeperTestSuite struct {
+    suite.Suite
+
+    ctx      sdk.Context
+    keeper   htlc.Keeper
+    bank     *testutil.MockBankKeeper
+    feegrant *testutil.MockFeegrantKeeper
+
+    sender    sdk.AccAddress
+    receiver  sdk.AccAddress
+    other     sdk.AccAddress
+    authority sdk.AccAddress
+}
+
+func TestKeeperTestSuite(t *testing.T) {
+    suite.Run(t, new(KeeperTestSuite))
+}
+
+func (s *KeeperTestSuite) SetupTest() {
+    db := dbm.NewMemDB()
+    cms := store.NewCommitMultiStore(db)
+    key := sdk.NewKVStoreKey("htlc")
+    cms.MountStoreWithDB(key, sdk.StoreTypeIAVL, db)
+    s.Require().NoError(cms.LoadLatestVersion())
+
+    cdc := codec.NewProtoCodec(codectypes.NewInterfaceRegistry())
+    s.bank = testutil.NewMockBankKeeper()
+    s.authority = sdk.AccAddress([]byte("gov_______________"))
+    s.feegrant = testutil.NewMockFeegrantKeeper()
+    s.keeper = htlc.NewKeeper(cdc, key, s.bank, s.feegrant, s.authority.String())
+    s.keeper.SetFeegrantMsgServer(s.feegrant)
+    s.ctx = sdk.NewContext(cms, tmproto.Header{Height: 1, Time: genesisTime}, false, log.NewNopLogger())
+
+    s.sender = sdk.AccAddress([]byte("sender____________"))
+    s.receiver = sdk.AccAddress([]byte("receiver__________"))
+    s.other = sdk.AccAddress([]byte("other_____________"))
+    s.bank.FundAccount(s.sender, sdk.NewCoins(sdk.NewInt64Coin("atom", 1000)))
+}
+
+// SetBlockTime moves the clock to t and starts a new block
+func (s *KeeperTestSuite) SetBlockTime(t time.Time) {
+    s.ctx = s.ctx.WithBlockHeight(s.ctx.BlockHeight() + 1).WithBlockTime(t)
+}
+
+// AdvanceTime moves the clock forward by d in a single new block
+func (s *KeeperTestSuite) AdvanceTime(d time.Duration) {
+    s.SetBlockTime(s.ctx.BlockTime().Add(d))
+}
+
+// AdvanceBlocks produces n blocks, each blockTime apart
+func (s *KeeperTestSuite) AdvanceBlocks(n int64, blockTime time.Duration) {
+    s.ctx = s.ctx.
+        WithBlockHeight(s.ctx.BlockHeight() + n).
+        WithBlockTime(s.ctx.BlockTime().Add(time.Duration(n) * blockTime))
+}
+
+// CreateHTLC locks 100atom from sender to receiver until now+lock and
+// returns the stored HTLC
+func (s *KeeperTestSuite) CreateHTLC(secret []byte, lock time.Duration) htlc.HTLC {
+    msg := htlc.MsgCreateHTLC{
+        Sender:   s.sender,
+        Receiver: s.receiver,
+        Amount:   sdk.NewCoins(sdk.NewInt64Coin("atom", 100)),
+        HashLock: sdk.Sha256(secret),
+        TimeLock: uint64(s.ctx.BlockTime().Add(lock).Unix()),
+    }
+    id, err := s.keeper.CreateHTLC(s.ctx, msg)
+    s.Require().NoError(err)
+
+    created, err := s.keeper.GetHTLC(s.ctx, id)
+    s.Require().NoError(err)
+    return created
+}
+
+// CreatePartialFill creates a partial-fill HTLC of 100atom over tree, one
+// part per secret
+func (s *KeeperTestSuite) CreatePartialFill(tree testutil.MerkleTree, lock time.Duration) htlc.HTLC {
+    msg := htlc.MsgCreateHTLC{
+        Sender:   s.sender,
+        Receiver: s.receiver,
+        Amount:   sdk.NewCoins(sdk.NewInt64Coin("atom", 100)),
+        HashLock: tree.Root,
+        TimeLock: uint64(s.ctx.BlockTime().Add(lock).Unix()),
+        Parts:    uint64(len(tree.Secrets)),
+    }
+    s.Require().NoError(msg.ValidateBasic())
+    id, err := s.keeper.CreateHTLC(s.ctx, msg)
+    s.Require().NoError(err)
+
+    created, err := s.keeper.GetHTLC(s.ctx, id)
+    s.Require().NoError(err)
+    return created
+}
+
+func (s *KeeperTestSuite) claim(id string, claimer sdk.AccAddress, secret []byte, proof [][]byte) error {
+    return s.keeper.ClaimHTLC(s.ctx, htlc.MsgClaimHTLC{Claimer: claimer, ID: id, Secret: secret, MerkleProof: proof})
+}
+
+func (s *KeeperTestSuite) refund(id string, signer sdk.AccAddress) error {
+    return s.keeper.RefundHTLC(s.ctx, htlc.MsgRefundHTLC{Sender: signer, ID: id})
+}
+
+func (s *KeeperTestSuite) TestClaim_OneSecondBeforeTimeLock() {
+    secret := []byte("secret")
+    created := s.CreateHTLC(secret, 2*time.Hour)
+
+    s.SetBlockTime(created.TimeLock.Add(-time.Second))
+    s.Require().NoError(s.claim(created.ID, s.receiver, secret, nil))
+    s.Require().Equal(created.Amount, s.bank.GetAllBalances(s.ctx, s.receiver))
+    s.Require().True(s.bank.ModuleBalance("htlc").IsZero())
+}
+
+func (s *KeeperTestSuite) TestClaim_AtTimeLock() {
+    secret := []byte("secret")
+    created := s.CreateHTLC(secret, 2*time.Hour)
+
+    s.SetBlockTime(created.TimeLock)
+    s.Require().ErrorIs(s.claim(created.ID, s.receiver, secret, nil), htlc.ErrExpired)
+    s.Require().Equal(created.Amount, s.bank.ModuleBalance("htlc"))
+}
+
+func (s *KeeperTestSuite) TestRefund_OneSecondBeforeTimeLock() {
+    created := s.CreateHTLC([]byte("secret"), 2*time.Hour)
+
+    s.SetBlockTime(created.TimeLock.Add(-time.Second))
+    s.Require().ErrorIs(s.refund(created.ID, s.sender), htlc.ErrNotExpired)
+}
+
+func (s *KeeperTestSuite) TestRefund_AtTimeLock() {
+    created := s.CreateHTLC([]byte("secret"), 2*time.Hour)
+
+    s.SetBlockTime(created.TimeLock)
+    s.Require().ErrorIs(s.refund(created.ID, s.other), htlc.ErrNotSender)
+    s.Require().NoError(s.refund(created.ID, s.sender))
+    s.Require().Equal(sdk.NewCoins(sdk.NewInt64Coin("atom", 1000)), s.bank.GetAllBalances(s.ctx, s.sender))
+}
+
+func (s *KeeperTestSuite) TestPublicWithdrawalBoundary() {
+    secret := []byte("secret")
+    created := s.CreateHTLC(secret, 2*time.Hour)
+    opensAt := created.TimeLock.Add(-htlc.DefaultPublicWithdrawalPeriod)
+
+    s.SetBlockTime(opensAt.Add(-time.Second))
+    s.Require().ErrorIs(s.claim(created.ID, s.other, secret, nil), htlc.ErrNotReceiver)
+
+    s.SetBlockTime(opensAt)
+    s.Require().NoError(s.claim(created.ID, s.other, secret, nil))
+    // A public withdrawal still pays the receiver
+    s.Require().Equal(created.Amount, s.bank.GetAllBalances(s.ctx, s.receiver))
+}
+
+func (s *KeeperTestSuite) TestPublicCancellationBoundary() {
+    created := s.CreateHTLC([]byte("secret"), 2*time.Hour)
+    opensAt := created.TimeLock.Add(htlc.DefaultPublicCancellationDelay)
+
+    s.SetBlockTime(opensAt.Add(-time.Second))
+    s.Require().ErrorIs(s.refund(created.ID, s.other), htlc.ErrNotSender)
+
+    s.SetBlockTime(opensAt)
+    s.Require().NoError(s.refund(created.ID, s.other))
+    s.Require().Equal(sdk.NewCoins(sdk.NewInt64Coin("atom", 1000)), s.bank.GetAllBalances(s.ctx, s.sender))
+}
+
+func (s *KeeperTestSuite) TestClaim_TargetRedirectsPayout() {
+    secret := []byte("secret")
+    created := s.CreateHTLC(secret, 2*time.Hour)
+
+    s.AdvanceTime(time.Minute)
+    s.Require().NoError(s.keeper.ClaimHTLC(s.ctx, htlc.NewMsgClaimHTLC(s.receiver, created.ID, secret, nil, s.other)))
+    s.Require().Equal(created.Amount, s.bank.GetAllBalances(s.ctx, s.other))
+    s.Require().True(s.bank.GetAllBalances(s.ctx, s.receiver).IsZero())
+}
+
+func (s *KeeperTestSuite) TestClaim_PublicWithdrawerCannotSetTarget() {
+    secret := []byte("secret")
+    created := s.CreateHTLC(secret, 2*time.Hour)
+
+    s.SetBlockTime(created.TimeLock.Add(-htlc.DefaultPublicWithdrawalPeriod))
+    err := s.keeper.ClaimHTLC(s.ctx, htlc.NewMsgClaimHTLC(s.other, created.ID, secret, nil, s.other))
+    s.Require().ErrorIs(err, htlc.ErrNotReceiver)
+    s.Require().Equal(created.Amount, s.bank.ModuleBalance("htlc"))
+}
+
+func (s *KeeperTestSuite) TestClaim_EmptyTargetPaysReceiver() {
+    secret := []byte("secret")
+    created := s.CreateHTLC(secret, 2*time.Hour)
+
+    s.AdvanceTime(time.Minute)
+    s.Require().NoError(s.keeper.ClaimHTLC(s.ctx, htlc.NewMsgClaimHTLC(s.receiver, created.ID, secret, nil, sdk.AccAddress{})))
+    s.Require().Equal(created.Amount, s.bank.GetAllBalances(s.ctx, s.receiver))
+}
+
+func (s *KeeperTestSuite) TestPartialFill() {
+    tree := testutil.BuildMerkleTree(testutil.NewMerkleSecrets(5))
+    created := s.CreatePartialFill(tree, 2*time.Hour)
+
+    for i := range tree.Secrets {
+        s.Require().True(htlc.VerifyMerkleProof(sdk.Sha256(tree.Secrets[i]), tree.Proofs[i], tree.Root), "proof %d", i)
+    }
+
+    s.Require().ErrorIs(s.claim(created.ID, s.receiver, tree.Secrets[0], tree.Proofs[1]), htlc.ErrInvalidProof)
+    s.Require().NoError(s.claim(created.ID, s.receiver, tree.Secrets[0], tree.Proofs[0]))
+    s.Require().ErrorIs(s.claim(created.ID, s.receiver, tree.Secrets[0], tree.Proofs[0]), htlc.ErrSecretReused)
+
+    // Each fill releases its fifth of the amount only
+    s.Require().Equal(sdk.NewCoins(sdk.NewInt64Coin("atom", 20)), s.bank.GetAllBalances(s.ctx, s.receiver))
+    s.Require().Equal(sdk.NewCoins(sdk.NewInt64Coin("atom", 80)), s.bank.ModuleBalance("htlc"))
+    status, err := s.keeper.HTLCStatus(s.ctx, created.ID)
+    s.Require().NoError(err)
+    s.Require().Equal(htlc.StatusPartiallyFilled, status)
+
+    for i := 1; i < len(tree.Secrets); i++ {
+        s.Require().NoError(s.claim(created.ID, s.receiver, tree.Secrets[i], tree.Proofs[i]))
+        s.Require().Equal(sdk.NewCoins(sdk.NewInt64Coin("atom", int64(20*(i+1)))), s.bank.GetAllBalances(s.ctx, s.receiver))
+    }
+    s.Require().True(s.bank.ModuleBalance("htlc").IsZero())
+    status, err = s.keeper.HTLCStatus(s.ctx, created.ID)
+    s.Require().NoError(err)
+    s.Require().Equal(htlc.StatusClaimed, status)
+}
+
+func (s *KeeperTestSuite) TestPartialFill_LastFillTakesRemainder() {
+    tree := testutil.BuildMerkleTree(testutil.NewMerkleSecrets(3))
+    created := s.CreatePartialFill(tree, 2*time.Hour)
+
+    for i, want := range []int64{33, 66, 100} {
+        s.Require().NoError(s.claim(created.ID, s.receiver, tree.Secrets[i], tree.Proofs[i]))
+        s.Require().Equal(sdk.NewCoins(sdk.NewInt64Coin("atom", want)), s.bank.GetAllBalances(s.ctx, s.receiver))
+    }
+    s.Require().True(s.bank.ModuleBalance("htlc").IsZero())
+}
+
+func (s *KeeperTestSuite) TestPartialFill_RefundReturnsUnfilledParts() {
+    tree := testutil.BuildMerkleTree(testutil.NewMerkleSecrets(5))
+    created := s.CreatePartialFill(tree, 2*time.Hour)
+    s.Require().NoError(s.claim(created.ID, s.receiver, tree.Secrets[0], tree.Proofs[0]))
+    s.Require().NoError(s.claim(created.ID, s.receiver, tree.Secrets[3], tree.Proofs[3]))
+
+    s.SetBlockTime(created.TimeLock)
+    s.Require().NoError(s.refund(created.ID, s.sender))
+    s.Require().Equal(sdk.NewCoins(sdk.NewInt64Coin("atom", 960)), s.bank.GetAllBalances(s.ctx, s.sender))
+    s.Require().Equal(sdk.NewCoins(sdk.NewInt64Coin("atom", 40)), s.bank.GetAllBalances(s.ctx, s.receiver))
+    s.Require().True(s.bank.ModuleBalance("htlc").IsZero())
+}
+
+func (s *KeeperTestSuite) TestRevealedSecretRetention() {
+    secret := []byte("secret")
+    created := s.CreateHTLC(secret, 2*time.Hour)
+    s.AdvanceTime(time.Minute)
+    s.Require().NoError(s.claim(created.ID, s.receiver, secret, nil))
+    claimedAt := s.ctx.BlockHeight()
+
+    retention := int64(s.keeper.GetParams(s.ctx).RevealedSecretRetention)
+    s.AdvanceBlocks(retention-1, 6*time.Second)
+    s.keeper.PruneRevealedSecrets(s.ctx)
+    s.Require().True(s.keeper.HasRevealedSecret(s.ctx, created.HashLock))
+
+    s.AdvanceBlocks(1, 6*time.Second)
+    s.Require().Equal(claimedAt+retention, s.ctx.BlockHeight())
+    s.keeper.PruneRevealedSecrets(s.ctx)
+    s.Require().False(s.keeper.HasRevealedSecret(s.ctx, created.HashLock))
+}
+
+func (s *KeeperTestSuite) TestClaimGas_ChargedPerProofNode() {
+    tree := testutil.BuildMerkleTree(testutil.NewMerkleSecrets(16))
+    created := s.CreatePartialFill(tree, 2*time.Hour)
+
+    s.ctx = s.ctx.WithGasMeter(sdk.NewInfiniteGasMeter())
+    s.Require().NoError(s.claim(created.ID, s.receiver, tree.Secrets[3], tree.Proofs[3]))
+    minGas := htlc.GasPerProofNode*uint64(len(tree.Proofs[3])) + htlc.GasPerUsedSecret
+    s.Require().GreaterOrEqual(s.ctx.GasMeter().GasConsumed(), minGas)
+
+    // A rejected oversized proof still pays for every node it carried
+    oversized := make([][]byte, 2*htlc.MaxMerkleProofDepth)
+    for i := range oversized {
+        oversized[i] = tree.Root
+    }
+    s.ctx = s.ctx.WithGasMeter(sdk.NewInfiniteGasMeter())
+    s.Require().ErrorIs(s.claim(created.ID, s.receiver, tree.Secrets[4], oversized), htlc.ErrInvalidProof)
+    s.Require().GreaterOrEqual(s.ctx.GasMeter().GasConsumed(), htlc.GasPerProofNode*uint64(len(oversized)))
+}
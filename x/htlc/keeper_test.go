@@ -2,6 +2,7 @@
 package htlc_test
 
 import (
+    "strings"
     "testing"
     "time"
 
@@ -32,8 +33,9 @@ func createTestInput(t *testing.T) (sdk.Context, htlc.Keeper, keeper.Keeper) {
     cdc := codec.NewProtoCodec(module.NewBasicManager())
 
     bankKeeper := keeper.NewBaseKeeper(cdc, bankKey, nil, nil)
-    k := htlc.NewKeeper(cdc, key)
-    ctx := sdk.NewContext(cms, tmproto.Header{Time: time.Now()}, false, log.NewNopLogger())
+    govAddr := sdk.AccAddress([]byte("gov_______________"))
+    k := htlc.NewKeeper(cdc, key, bankKeeper, nil, govAddr.String())
+    ctx := sdk.NewContext(cms, tmproto.Header{Time: genesisTime}, false, log.NewNopLogger())
 
     // Fund sender account
     sender := sdk.AccAddress([]byte("sender____________"))
@@ -52,7 +54,7 @@ func TestCreateHTLC(t *testing.T) {
     receiver := sdk.AccAddress([]byte("receiver__________"))
     amount := sdk.NewCoins(sdk.NewInt64Coin("atom", 100))
     hashLock := sdk.Sha256([]byte("secret"))
-    timeLock := uint64(ctx.BlockTime().Add(time.Hour).Unix())
+    timeLock := uint64(ctx.BlockTime().Add(2 * time.Hour).Unix())
 
     msg := htlc.MsgCreateHTLC{
         Sender:   sender,
@@ -62,11 +64,11 @@ func TestCreateHTLC(t *testing.T) {
         TimeLock: timeLock,
     }
 
-    err := k.CreateHTLC(ctx, msg)
+    _, err := k.CreateHTLC(ctx, msg)
     require.NoError(t, err)
 
     store := k.GetStore(ctx)
-    id := sender.String() + "-" + ctx.BlockTime().String()
+    id := htlc.HTLCID(sender, ctx.BlockTime())
     bz := store.Get([]byte(id))
     require.NotNil(t, bz)
 }
@@ -78,7 +80,7 @@ func TestClaimHTLC_Success(t *testing.T) {
     secret := []byte("secret")
     hashLock := sdk.Sha256(secret)
     amount := sdk.NewCoins(sdk.NewInt64Coin("atom", 100))
-    timeLock := uint64(ctx.BlockTime().Add(time.Hour).Unix())
+    timeLock := uint64(ctx.BlockTime().Add(2 * time.Hour).Unix())
 
     createMsg := htlc.MsgCreateHTLC{
         Sender:   sender,
@@ -87,10 +89,10 @@ func TestClaimHTLC_Success(t *testing.T) {
         HashLock: hashLock,
         TimeLock: timeLock,
     }
-    err := k.CreateHTLC(ctx, createMsg)
+    _, err := k.CreateHTLC(ctx, createMsg)
     require.NoError(t, err)
 
-    id := sender.String() + "-" + ctx.BlockTime().String()
+    id := htlc.HTLCID(sender, ctx.BlockTime())
     claimMsg := htlc.MsgClaimHTLC{
         Claimer: receiver,
         ID:      id,
@@ -115,7 +117,7 @@ func TestClaimHTLC_InvalidSecret(t *testing.T) {
     wrongSecret := []byte("wrongsecret")
     hashLock := sdk.Sha256(secret)
     amount := sdk.NewCoins(sdk.NewInt64Coin("atom", 100))
-    timeLock := uint64(ctx.BlockTime().Add(time.Hour).Unix())
+    timeLock := uint64(ctx.BlockTime().Add(2 * time.Hour).Unix())
 
     createMsg := htlc.MsgCreateHTLC{
         Sender:   sender,
@@ -124,17 +126,17 @@ func TestClaimHTLC_InvalidSecret(t *testing.T) {
         HashLock: hashLock,
         TimeLock: timeLock,
     }
-    err := k.CreateHTLC(ctx, createMsg)
+    _, err := k.CreateHTLC(ctx, createMsg)
     require.NoError(t, err)
 
-    id := sender.String() + "-" + ctx.BlockTime().String()
+    id := htlc.HTLCID(sender, ctx.BlockTime())
     claimMsg := htlc.MsgClaimHTLC{
         Claimer: receiver,
         ID:      id,
         Secret:  wrongSecret,
     }
     err = k.ClaimHTLC(ctx, claimMsg)
-    require.Error(t, err)
+    require.ErrorIs(t, err, htlc.ErrInvalidSecret)
 }
 
 func TestRefundHTLC_Success(t *testing.T) {
@@ -144,7 +146,7 @@ func TestRefundHTLC_Success(t *testing.T) {
     secret := []byte("secret")
     hashLock := sdk.Sha256(secret)
     amount := sdk.NewCoins(sdk.NewInt64Coin("atom", 100))
-    timeLock := uint64(ctx.BlockTime().Add(-time.Hour).Unix()) // expired
+    timeLock := uint64(ctx.BlockTime().Add(2 * time.Hour).Unix())
 
     createMsg := htlc.MsgCreateHTLC{
         Sender:   sender,
@@ -153,10 +155,11 @@ func TestRefundHTLC_Success(t *testing.T) {
         HashLock: hashLock,
         TimeLock: timeLock,
     }
-    err := k.CreateHTLC(ctx, createMsg)
+    _, err := k.CreateHTLC(ctx, createMsg)
     require.NoError(t, err)
 
-    id := sender.String() + "-" + ctx.BlockTime().String()
+    id := htlc.HTLCID(sender, ctx.BlockTime())
+    ctx = ctx.WithBlockTime(ctx.BlockTime().Add(3 * time.Hour)) // expired
     refundMsg := htlc.MsgRefundHTLC{
         Sender: sender,
         ID:     id,
@@ -179,7 +182,7 @@ func TestRefundHTLC_NotExpired(t *testing.T) {
     secret := []byte("secret")
     hashLock := sdk.Sha256(secret)
     amount := sdk.NewCoins(sdk.NewInt64Coin("atom", 100))
-    timeLock := uint64(ctx.BlockTime().Add(time.Hour).Unix()) // not expired
+    timeLock := uint64(ctx.BlockTime().Add(2 * time.Hour).Unix()) // not expired
 
     createMsg := htlc.MsgCreateHTLC{
         Sender:   sender,
@@ -188,14 +191,267 @@ func TestRefundHTLC_NotExpired(t *testing.T) {
         HashLock: hashLock,
         TimeLock: timeLock,
     }
-    err := k.CreateHTLC(ctx, createMsg)
+    _, err := k.CreateHTLC(ctx, createMsg)
     require.NoError(t, err)
 
-    id := sender.String() + "-" + ctx.BlockTime().String()
+    id := htlc.HTLCID(sender, ctx.BlockTime())
     refundMsg := htlc.MsgRefundHTLC{
         Sender: sender,
         ID:     id,
     }
     err = k.RefundHTLC(ctx, refundMsg)
-    require.Error(t, err)
+    require.ErrorIs(t, err, htlc.ErrNotExpired)
+}
+
+func TestClaimHTLC_AfterRefund(t *testing.T) {
+    ctx, k, _ := createTestInput(t)
+    sender := sdk.AccAddress([]byte("sender____________"))
+    receiver := sdk.AccAddress([]byte("receiver__________"))
+    secret := []byte("secret")
+    hashLock := sdk.Sha256(secret)
+    amount := sdk.NewCoins(sdk.NewInt64Coin("atom", 100))
+    timeLock := uint64(ctx.BlockTime().Add(2 * time.Hour).Unix())
+
+    createMsg := htlc.MsgCreateHTLC{
+        Sender:   sender,
+        Receiver: receiver,
+        Amount:   amount,
+        HashLock: hashLock,
+        TimeLock: timeLock,
+    }
+    _, err := k.CreateHTLC(ctx, createMsg)
+    require.NoError(t, err)
+
+    id := htlc.HTLCID(sender, ctx.BlockTime())
+    ctx = ctx.WithBlockTime(ctx.BlockTime().Add(3 * time.Hour)) // expired
+    err = k.RefundHTLC(ctx, htlc.MsgRefundHTLC{Sender: sender, ID: id})
+    require.NoError(t, err)
+
+    err = k.ClaimHTLC(ctx, htlc.MsgClaimHTLC{Claimer: receiver, ID: id, Secret: secret})
+    require.ErrorIs(t, err, htlc.ErrAlreadyRefunded)
+    err = k.RefundHTLC(ctx, htlc.MsgRefundHTLC{Sender: sender, ID: id})
+    require.ErrorIs(t, err, htlc.ErrAlreadyRefunded)
+}
+
+func TestRefundHTLC_AfterClaim(t *testing.T) {
+    ctx, k, _ := createTestInput(t)
+    sender := sdk.AccAddress([]byte("sender____________"))
+    receiver := sdk.AccAddress([]byte("receiver__________"))
+    secret := []byte("secret")
+    hashLock := sdk.Sha256(secret)
+    amount := sdk.NewCoins(sdk.NewInt64Coin("atom", 100))
+    timeLock := uint64(ctx.BlockTime().Add(2 * time.Hour).Unix())
+
+    createMsg := htlc.MsgCreateHTLC{
+        Sender:   sender,
+        Receiver: receiver,
+        Amount:   amount,
+        HashLock: hashLock,
+        TimeLock: timeLock,
+    }
+    _, err := k.CreateHTLC(ctx, createMsg)
+    require.NoError(t, err)
+
+    id := htlc.HTLCID(sender, ctx.BlockTime())
+    err = k.ClaimHTLC(ctx, htlc.MsgClaimHTLC{Claimer: receiver, ID: id, Secret: secret})
+    require.NoError(t, err)
+
+    err = k.ClaimHTLC(ctx, htlc.MsgClaimHTLC{Claimer: receiver, ID: id, Secret: secret})
+    require.ErrorIs(t, err, htlc.ErrAlreadyClaimed)
+
+    // Even once expired, a claimed HTLC can't be refunded
+    ctx = ctx.WithBlockTime(ctx.BlockTime().Add(3 * time.Hour))
+    err = k.RefundHTLC(ctx, htlc.MsgRefundHTLC{Sender: sender, ID: id})
+    require.ErrorIs(t, err, htlc.ErrAlreadyClaimed)
+
+    status, err := k.HTLCStatus(ctx, id)
+    require.NoError(t, err)
+    require.Equal(t, htlc.StatusClaimed, status)
+}
+
+func TestGetHTLCsByIndex(t *testing.T) {
+    ctx, k, _ := createTestInput(t)
+    sender := sdk.AccAddress([]byte("sender____________"))
+    receiver := sdk.AccAddress([]byte("receiver__________"))
+    secret := []byte("secret")
+    hashLock := sdk.Sha256(secret)
+    amount := sdk.NewCoins(sdk.NewInt64Coin("atom", 100))
+    timeLock := uint64(ctx.BlockTime().Add(2 * time.Hour).Unix())
+
+    createMsg := htlc.MsgCreateHTLC{
+        Sender:        sender,
+        Receiver:      receiver,
+        Amount:        amount,
+        HashLock:      hashLock,
+        TimeLock:      timeLock,
+        ExternalChain: "ethereum",
+        ExternalID:    "0xescrow",
+    }
+    _, err := k.CreateHTLC(ctx, createMsg)
+    require.NoError(t, err)
+    id := htlc.HTLCID(sender, ctx.BlockTime())
+
+    bySender, err := k.GetHTLCsBySender(ctx, sender)
+    require.NoError(t, err)
+    require.Len(t, bySender, 1)
+    require.Equal(t, id, bySender[0].ID)
+
+    byReceiver, err := k.GetHTLCsByReceiver(ctx, receiver)
+    require.NoError(t, err)
+    require.Len(t, byReceiver, 1)
+
+    byHashLock, err := k.GetHTLCsByHashLock(ctx, hashLock)
+    require.NoError(t, err)
+    require.Len(t, byHashLock, 1)
+
+    byExternal, err := k.GetHTLCsByExternalID(ctx, "ethereum", "0xescrow")
+    require.NoError(t, err)
+    require.Len(t, byExternal, 1)
+
+    // Claiming keeps the index entries pointing at the updated record
+    err = k.ClaimHTLC(ctx, htlc.MsgClaimHTLC{Claimer: receiver, ID: id, Secret: secret})
+    require.NoError(t, err)
+    byExternal, err = k.GetHTLCsByExternalID(ctx, "ethereum", "0xescrow")
+    require.NoError(t, err)
+    require.Len(t, byExternal, 1)
+    require.True(t, byExternal[0].Claimed)
+
+    none, err := k.GetHTLCsBySender(ctx, receiver)
+    require.NoError(t, err)
+    require.Empty(t, none)
+}
+
+func TestExternalIDIndex_OversizedKey(t *testing.T) {
+    ctx, k, _ := createTestInput(t)
+    long := strings.Repeat("i", htlc.MaxExternalFieldLength+1)
+
+    // Keepers called without ValidateBasic get an error instead of a panic
+    msg := htlc.MsgCreateHTLC{
+        Sender:        sdk.AccAddress([]byte("sender____________")),
+        Receiver:      sdk.AccAddress([]byte("receiver__________")),
+        Amount:        sdk.NewCoins(sdk.NewInt64Coin("atom", 100)),
+        HashLock:      sdk.Sha256([]byte("secret")),
+        TimeLock:      uint64(ctx.BlockTime().Add(2 * time.Hour).Unix()),
+        ExternalChain: "ethereum",
+        ExternalID:    long,
+    }
+    require.NotPanics(t, func() {
+        _, err := k.CreateHTLC(ctx, msg)
+        require.Error(t, err)
+    })
+    require.NotPanics(t, func() {
+        _, err := k.GetHTLCsByExternalID(ctx, "ethereum", long)
+        require.Error(t, err)
+        _, err = k.GetHTLCsByExternalID(ctx, long, "0xescrow")
+        require.Error(t, err)
+    })
+}
+
+func TestCreateHTLC_SameBlockIDs(t *testing.T) {
+    ctx, k, _ := createTestInput(t)
+    sender := sdk.AccAddress([]byte("sender____________"))
+    receiver := sdk.AccAddress([]byte("receiver__________"))
+    create := func(secret string) htlc.MsgCreateHTLC {
+        return htlc.MsgCreateHTLC{
+            Sender:   sender,
+            Receiver: receiver,
+            Amount:   sdk.NewCoins(sdk.NewInt64Coin("atom", 100)),
+            HashLock: sdk.Sha256([]byte(secret)),
+            TimeLock: uint64(ctx.BlockTime().Add(2 * time.Hour).Unix()),
+        }
+    }
+    base := htlc.HTLCID(sender, ctx.BlockTime())
+
+    // Separate txs of one sender in one block get distinct IDs
+    first, err := k.CreateHTLC(ctx, create("first"))
+    require.NoError(t, err)
+    require.Equal(t, base, first)
+    second, err := k.CreateHTLC(ctx, create("second"))
+    require.NoError(t, err)
+    require.Equal(t, base+".1", second)
+
+    // and so do batches
+    for _, want := range []string{base + "-0", base + "-0.1"} {
+        results, err := k.BatchCreate(ctx, htlc.MsgBatchCreate{Creates: []htlc.MsgCreateHTLC{create(want)}})
+        require.NoError(t, err)
+        require.Equal(t, want, results[0].ID)
+    }
+
+    for _, id := range []string{first, second, base + "-0", base + "-0.1"} {
+        stored, err := k.GetHTLC(ctx, id)
+        require.NoError(t, err)
+        require.Equal(t, id, stored.ID)
+    }
+}
+
+func TestCreateHTLC_DuplicateHashLock(t *testing.T) {
+    ctx, k, _ := createTestInput(t)
+    sender := sdk.AccAddress([]byte("sender____________"))
+    receiver := sdk.AccAddress([]byte("receiver__________"))
+    otherReceiver := sdk.AccAddress([]byte("other_receiver____"))
+    hashLock := sdk.Sha256([]byte("secret"))
+    amount := sdk.NewCoins(sdk.NewInt64Coin("atom", 100))
+    timeLock := uint64(ctx.BlockTime().Add(2 * time.Hour).Unix())
+
+    createMsg := htlc.MsgCreateHTLC{
+        Sender:   sender,
+        Receiver: receiver,
+        Amount:   amount,
+        HashLock: hashLock,
+        TimeLock: timeLock,
+    }
+    _, err := k.CreateHTLC(ctx, createMsg)
+    require.NoError(t, err)
+
+    ctx = ctx.WithBlockTime(ctx.BlockTime().Add(time.Second))
+    _, err = k.CreateHTLC(ctx, createMsg)
+    require.ErrorIs(t, err, htlc.ErrHashLockInUse)
+
+    createMsg.Receiver = otherReceiver
+    _, err = k.CreateHTLC(ctx, createMsg)
+    require.NoError(t, err)
+
+    usage, err := k.GetHashLockUsage(ctx, hashLock)
+    require.NoError(t, err)
+    require.Len(t, usage.OpenIDs, 2)
+    require.False(t, usage.Revealed)
+    require.NotEmpty(t, usage.Warning)
+}
+
+func TestRevealedSecret_StoredAndPruned(t *testing.T) {
+    ctx, k, _ := createTestInput(t)
+    ctx = ctx.WithBlockHeight(10)
+    sender := sdk.AccAddress([]byte("sender____________"))
+    receiver := sdk.AccAddress([]byte("receiver__________"))
+    secret := []byte("secret")
+    hashLock := sdk.Sha256(secret)
+    amount := sdk.NewCoins(sdk.NewInt64Coin("atom", 100))
+    timeLock := uint64(ctx.BlockTime().Add(2 * time.Hour).Unix())
+
+    createMsg := htlc.MsgCreateHTLC{
+        Sender:   sender,
+        Receiver: receiver,
+        Amount:   amount,
+        HashLock: hashLock,
+        TimeLock: timeLock,
+    }
+    _, err := k.CreateHTLC(ctx, createMsg)
+    require.NoError(t, err)
+
+    id := htlc.HTLCID(sender, ctx.BlockTime())
+    err = k.ClaimHTLC(ctx, htlc.MsgClaimHTLC{Claimer: receiver, ID: id, Secret: secret})
+    require.NoError(t, err)
+
+    revealed, err := k.GetRevealedSecret(ctx, hashLock)
+    require.NoError(t, err)
+    require.Equal(t, secret, revealed.Secret)
+    require.Equal(t, id, revealed.HTLCID)
+    require.Equal(t, int64(10), revealed.Height)
+
+    retention := int64(k.GetParams(ctx).RevealedSecretRetention)
+    k.PruneRevealedSecrets(ctx.WithBlockHeight(10 + retention - 1))
+    require.True(t, k.HasRevealedSecret(ctx, hashLock))
+
+    k.PruneRevealedSecrets(ctx.WithBlockHeight(10 + retention))
+    require.False(t, k.HasRevealedSecret(ctx, hashLock))
 }
@@ -0,0 +1,191 @@
+// x/htlc/migrations.go
+package htlc
+
+import (
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/cosmos/cosmos-sdk/store/prefix"
+    sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// ConsensusVersion is the version of the htlc store layout. Bump it and
+// register a migration whenever the stored records or keys change.
+const ConsensusVersion = 2
+
+// Migrator upgrades the htlc store between consensus versions
+type Migrator struct {
+    keeper Keeper
+}
+
+func NewMigrator(k Keeper) Migrator {
+    return Migrator{keeper: k}
+}
+
+// Migrate1to2 migrates the store from version 1 to 2:
+//   - IDs move from "<sender>-<time.String()>[-<index>]" to HTLCID's
+//     "<sender>-<unix seconds>[-<index>]", without spaces or zone names. IDs
+//     that collide at second precision get a ".<n>" suffix in store order.
+//   - used partial-fill secrets move out of the record into the used-secret
+//     store, the record only keeps their count. v1 paid the whole amount on
+//     the first fill, so records with a fill are marked claimed.
+//   - the locked balance is totalled once from the open HTLCs, later
+//     creates, claims, refunds and rescues keep it up to date
+//   - stored params get the default public withdrawal period and public
+//     cancellation delay, which used to be constants
+func (m Migrator) Migrate1to2(ctx sdk.Context) error {
+    return migrateV1ToV2(ctx, m.keeper)
+}
+
+// timeStringLayout is the layout of time.Time.String(), used by v1 IDs
+const timeStringLayout = "2006-01-02 15:04:05.999999999 -0700 MST"
+
+func migrateV1ToV2(ctx sdk.Context, k Keeper) error {
+    // Decode everything first, the store can't be written while iterating it
+    var olds []HTLCV1
+    iterator := k.getHTLCStore(ctx).Iterator(nil, nil)
+    for ; iterator.Valid(); iterator.Next() {
+        var old HTLCV1
+        if err := k.cdc.Unmarshal(iterator.Value(), &old); err != nil {
+            iterator.Close()
+            return err
+        }
+        olds = append(olds, old)
+    }
+    iterator.Close()
+
+    renamed := make(map[string]string, len(olds))
+    for _, old := range olds {
+        htlc := HTLC{
+            ID:            old.ID,
+            Sender:        old.Sender,
+            Receiver:      old.Receiver,
+            Amount:        old.Amount,
+            HashLock:      old.HashLock,
+            TimeLock:      old.TimeLock,
+            Claimed:       old.Claimed,
+            Refunded:      old.Refunded,
+            ExternalChain: old.ExternalChain,
+            ExternalID:    old.ExternalID,
+            MerkleRoot:    old.MerkleRoot,
+        }
+        if err := k.deleteHTLC(ctx, htlc); err != nil {
+            return err
+        }
+
+        newID, ok := migrateHTLCID(old)
+        if !ok {
+            ctx.Logger().Error("keeping unparsable htlc id", "id", old.ID)
+        }
+        // v1 IDs had nanosecond precision, so several of them can map to the
+        // same second
+        if unique := k.uniqueHTLCID(ctx, newID); unique != newID {
+            ctx.Logger().Info("suffixing colliding htlc id", "id", old.ID, "new_id", unique)
+            newID = unique
+        }
+        htlc.ID = newID
+        renamed[old.ID] = newID
+
+        for secret, used := range old.UsedSecrets {
+            if !used {
+                continue
+            }
+            k.setSecretUsed(ctx, htlc.ID, sdk.Sha256([]byte(secret)))
+            htlc.FillCount++
+        }
+        // v1 paid the whole amount out on the first fill, so a filled record
+        // holds nothing anymore
+        if htlc.FillCount > 0 {
+            htlc.Claimed = true
+        }
+
+        if err := k.setHTLC(ctx, htlc); err != nil {
+            return err
+        }
+    }
+
+    if err := k.resetLockedBalance(ctx); err != nil {
+        return err
+    }
+    if err := migrateParams(ctx, k); err != nil {
+        return err
+    }
+    return migrateRevealedSecretIDs(ctx, k, renamed)
+}
+
+// migrateParams sets the public stages on params stored before they were
+// params. Without stored params GetParams already returns the defaults.
+func migrateParams(ctx sdk.Context, k Keeper) error {
+    if !ctx.KVStore(k.storeKey).Has(ParamsKey) {
+        return nil
+    }
+    params := k.GetParams(ctx)
+    if params.PublicWithdrawalPeriod == 0 {
+        params.PublicWithdrawalPeriod = DefaultPublicWithdrawalPeriod
+    }
+    if params.PublicCancellationDelay == 0 {
+        params.PublicCancellationDelay = DefaultPublicCancellationDelay
+    }
+    return k.SetParams(ctx, params)
+}
+
+// migrateHTLCID converts a v1 ID to the v2 scheme. It returns the old ID and
+// false if the ID doesn't follow the v1 scheme.
+func migrateHTLCID(old HTLCV1) (string, bool) {
+    sender := old.Sender.String()
+    if !strings.HasPrefix(old.ID, sender+"-") {
+        return old.ID, false
+    }
+    rest := strings.TrimPrefix(old.ID, sender+"-")
+
+    if createdAt, err := time.Parse(timeStringLayout, rest); err == nil {
+        return HTLCID(old.Sender, createdAt), true
+    }
+
+    // Batch-created IDs carry a trailing item index
+    sep := strings.LastIndex(rest, "-")
+    if sep < 0 {
+        return old.ID, false
+    }
+    index, err := strconv.Atoi(rest[sep+1:])
+    if err != nil {
+        return old.ID, false
+    }
+    createdAt, err := time.Parse(timeStringLayout, rest[:sep])
+    if err != nil {
+        return old.ID, false
+    }
+    return HTLCID(old.Sender, createdAt) + "-" + strconv.Itoa(index), true
+}
+
+// migrateRevealedSecretIDs points revealed secrets at the renamed HTLCs
+func migrateRevealedSecretIDs(ctx sdk.Context, k Keeper, renamed map[string]string) error {
+    store := prefix.NewStore(ctx.KVStore(k.storeKey), RevealedSecretKeyPrefix)
+
+    var secrets []RevealedSecret
+    iterator := store.Iterator(nil, nil)
+    for ; iterator.Valid(); iterator.Next() {
+        var revealed RevealedSecret
+        if err := k.cdc.Unmarshal(iterator.Value(), &revealed); err != nil {
+            iterator.Close()
+            return err
+        }
+        secrets = append(secrets, revealed)
+    }
+    iterator.Close()
+
+    for _, revealed := range secrets {
+        newID, ok := renamed[revealed.HTLCID]
+        if !ok || newID == revealed.HTLCID {
+            continue
+        }
+        revealed.HTLCID = newID
+        bz, err := k.cdc.Marshal(&revealed)
+        if err != nil {
+            return err
+        }
+        store.Set(revealed.HashLock, bz)
+    }
+    return nil
+}
@@ -0,0 +1,135 @@
+// x/htlc/migrations_test.go
+package htlc
+
+import (
+    "encoding/json"
+    "os"
+    "testing"
+    "time"
+
+    "github.com/cosmos/cosmos-sdk/codec"
+    codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+    "github.com/cosmos/cosmos-sdk/store"
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    "github.com/stretchr/testify/require"
+    "github.com/tendermint/tendermint/libs/log"
+    tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+    dbm "github.com/tendermint/tm-db"
+)
+
+// loadV1Fixture returns a keeper and context whose store holds the v1 records
+// from testdata/v1_htlcs.json
+func loadV1Fixture(t *testing.T) (sdk.Context, Keeper, []HTLCV1) {
+    db := dbm.NewMemDB()
+    cms := store.NewCommitMultiStore(db)
+    key := sdk.NewKVStoreKey("htlc")
+    cms.MountStoreWithDB(key, sdk.StoreTypeIAVL, db)
+    require.NoError(t, cms.LoadLatestVersion())
+
+    cdc := codec.NewProtoCodec(codectypes.NewInterfaceRegistry())
+    k := NewKeeper(cdc, key, nil, nil, "")
+    ctx := sdk.NewContext(cms, tmproto.Header{Time: time.Unix(1_700_000_100, 0)}, false, log.NewNopLogger())
+
+    bz, err := os.ReadFile("testdata/v1_htlcs.json")
+    require.NoError(t, err)
+    var olds []HTLCV1
+    require.NoError(t, json.Unmarshal(bz, &olds))
+
+    htlcStore := k.getHTLCStore(ctx)
+    for i := range olds {
+        bz, err := cdc.Marshal(&olds[i])
+        require.NoError(t, err)
+        htlcStore.Set([]byte(olds[i].ID), bz)
+    }
+    return ctx, k, olds
+}
+
+func TestMigrate1to2(t *testing.T) {
+    ctx, k, olds := loadV1Fixture(t)
+    sender := olds[0].Sender
+
+    // v1 params had no public stages
+    v1Params := DefaultParams()
+    v1Params.RevealedSecretRetention = 7
+    v1Params.PublicWithdrawalPeriod, v1Params.PublicCancellationDelay = 0, 0
+    bz, err := k.cdc.Marshal(&v1Params)
+    require.NoError(t, err)
+    ctx.KVStore(k.storeKey).Set(ParamsKey, bz)
+
+    require.NoError(t, NewMigrator(k).Migrate1to2(ctx))
+
+    params := k.GetParams(ctx)
+    require.Equal(t, uint64(7), params.RevealedSecretRetention)
+    require.Equal(t, DefaultPublicWithdrawalPeriod, params.PublicWithdrawalPeriod)
+    require.Equal(t, DefaultPublicCancellationDelay, params.PublicCancellationDelay)
+
+    // Plain ID rewritten to the unix-seconds scheme
+    claimed, err := k.GetHTLC(ctx, HTLCID(sender, time.Unix(1_700_000_000, 0)))
+    require.NoError(t, err)
+    require.True(t, claimed.Claimed)
+    _, err = k.GetHTLC(ctx, olds[0].ID)
+    require.ErrorIs(t, err, ErrHTLCNotFound)
+
+    // A v1 ID with the same second is suffixed instead of overwriting it
+    collided, err := k.GetHTLC(ctx, claimed.ID+".1")
+    require.NoError(t, err)
+    require.True(t, collided.Refunded)
+    require.Equal(t, sdk.NewCoins(sdk.NewInt64Coin("atom", 7)), collided.Amount)
+    _, err = k.GetHTLC(ctx, claimed.ID+".2")
+    require.ErrorIs(t, err, ErrHTLCNotFound)
+
+    // Batch ID keeps its item index, used secrets moved to their own store
+    partial, err := k.GetHTLC(ctx, HTLCID(sender, time.Unix(1_700_000_006, 0))+"-1")
+    require.NoError(t, err)
+    require.Equal(t, uint64(2), partial.FillCount)
+    require.True(t, k.isSecretUsed(ctx, partial.ID, sdk.Sha256([]byte("first-secret"))))
+    require.True(t, k.isSecretUsed(ctx, partial.ID, sdk.Sha256([]byte("second-secret"))))
+    require.False(t, k.isSecretUsed(ctx, partial.ID, sdk.Sha256([]byte("third-secret"))))
+    // v1 paid partial fills out in full, so the record is claimed and
+    // nothing is left locked
+    require.True(t, partial.Claimed)
+    require.Equal(t, StatusClaimed, ComputeStatus(partial, k.GetParams(ctx), ctx.BlockTime()))
+    locked, err := k.LockedBalance(ctx)
+    require.NoError(t, err)
+    require.True(t, locked.IsZero(), "locked %s", locked)
+
+    // IDs outside the v1 scheme are kept as they are
+    legacy, err := k.GetHTLC(ctx, "legacy-import-7")
+    require.NoError(t, err)
+    require.True(t, legacy.Refunded)
+
+    // Indexes point at the new IDs
+    byExternal, err := k.GetHTLCsByExternalID(ctx, "ethereum", "0xescrow")
+    require.NoError(t, err)
+    require.Len(t, byExternal, 1)
+    require.Equal(t, claimed.ID, byExternal[0].ID)
+    bySender, err := k.GetHTLCsBySender(ctx, sender)
+    require.NoError(t, err)
+    require.Len(t, bySender, len(olds))
+}
+
+func TestMigrateHTLCID(t *testing.T) {
+    sender := sdk.AccAddress([]byte("sender____________"))
+    createdAt := time.Unix(1_700_000_000, 0).UTC()
+
+    cases := []struct {
+        name  string
+        oldID string
+        newID string
+        ok    bool
+    }{
+        {"single", sender.String() + "-" + createdAt.String(), HTLCID(sender, createdAt), true},
+        {"sub-second", sender.String() + "-" + createdAt.Add(500 * time.Millisecond).String(), HTLCID(sender, createdAt), true},
+        {"batch item", sender.String() + "-" + createdAt.String() + "-12", HTLCID(sender, createdAt) + "-12", true},
+        {"other sender", "cosmos1other-" + createdAt.String(), "cosmos1other-" + createdAt.String(), false},
+        {"garbage suffix", sender.String() + "-yesterday", sender.String() + "-yesterday", false},
+    }
+
+    for _, tc := range cases {
+        t.Run(tc.name, func(t *testing.T) {
+            newID, ok := migrateHTLCID(HTLCV1{ID: tc.oldID, Sender: sender})
+            require.Equal(t, tc.ok, ok)
+            require.Equal(t, tc.newID, newID)
+        })
+    }
+}
@@ -2,19 +2,49 @@
 package htlc
 
 import (
+    "context"
+    "fmt"
+
+    gwruntime "github.com/grpc-ecosystem/grpc-gateway/runtime"
+
+    "github.com/cosmos/cosmos-sdk/client"
+    "github.com/cosmos/cosmos-sdk/codec"
+    codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+    sdk "github.com/cosmos/cosmos-sdk/types"
     "github.com/cosmos/cosmos-sdk/types/module"
 )
 
-type AppModule struct{}
+var _ module.HasServices = AppModule{}
+
+// AppModule holds the keeper by pointer, so hooks and the light client set on
+// the keeper depinject provides reach the module's Msg and Query services
+type AppModule struct {
+    cdc    codec.Codec
+    keeper *Keeper
+}
 
-func NewAppModule() AppModule {
-    return AppModule{}
+func NewAppModule(cdc codec.Codec, k *Keeper) AppModule {
+    return AppModule{
+        cdc:    cdc,
+        keeper: k,
+    }
 }
 
 func (AppModule) Name() string {
     return "htlc"
 }
 
+func (AppModule) RegisterInterfaces(registry codectypes.InterfaceRegistry) {
+    RegisterInterfaces(registry)
+}
+
+// RegisterGRPCGatewayRoutes registers the REST routes of the Query service
+func (AppModule) RegisterGRPCGatewayRoutes(clientCtx client.Context, mux *gwruntime.ServeMux) {
+    if err := RegisterQueryHandlerClient(context.Background(), mux, NewQueryClient(clientCtx)); err != nil {
+        panic(err)
+    }
+}
+
 func (AppModule) RegisterInvariants(_ module.InvariantRegistry) {}
 
 func (AppModule) Route() string {
@@ -27,8 +57,26 @@ func (AppModule) QuerierRoute() string {
 
 func (AppModule) LegacyQuerierHandler(_ *module.LegacyQuerierHandler) {}
 
-func (AppModule) RegisterServices(_ module.Configurator) {}
+// RegisterServices registers the Msg and Query services and the store migrations
+func (am AppModule) RegisterServices(cfg module.Configurator) {
+    RegisterMsgServer(cfg.MsgServer(), NewMsgServerImpl(am.keeper))
+    RegisterQueryServer(cfg.QueryServer(), NewQueryServerImpl(am.keeper))
+
+    m := NewMigrator(*am.keeper)
+    if err := cfg.RegisterMigration("htlc", 1, m.Migrate1to2); err != nil {
+        panic(fmt.Sprintf("failed to migrate x/htlc from version 1 to 2: %v", err))
+    }
+}
+
+// ConsensusVersion implements AppModule/ConsensusVersion
+func (AppModule) ConsensusVersion() uint64 { return ConsensusVersion }
 
 func (AppModule) InitGenesis(_ module.Generator) {}
 
 func (AppModule) ExportGenesis(_ module.Generator) {}
+
+// EndBlock prunes expired entries of the revealed-secret registry
+func (am AppModule) EndBlock(ctx context.Context) error {
+    EndBlocker(sdk.UnwrapSDKContext(ctx), *am.keeper)
+    return nil
+}
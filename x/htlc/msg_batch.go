@@ -0,0 +1,122 @@
+// x/htlc/msg_batch.go
+package htlc
+
+import (
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// MaxBatchSize bounds the number of operations carried by a single batch message
+const MaxBatchSize = 100
+
+func NewMsgBatchCreate(sender sdk.AccAddress, creates []MsgCreateHTLC, bestEffort bool) MsgBatchCreate {
+    return MsgBatchCreate{
+        Sender:     sender,
+        Creates:    creates,
+        BestEffort: bestEffort,
+    }
+}
+
+func (msg MsgBatchCreate) Route() string { return "htlc" }
+
+func (msg MsgBatchCreate) Type() string { return "batch_create_htlc" }
+
+func (msg MsgBatchCreate) ValidateBasic() error {
+    if msg.Sender.Empty() {
+        return sdk.ErrInvalidAddress("missing sender address")
+    }
+    if err := validateBatchSize(len(msg.Creates)); err != nil {
+        return err
+    }
+    for i, create := range msg.Creates {
+        if !create.Sender.Equals(msg.Sender) {
+            return sdkerrors.Wrapf(sdkerrors.ErrUnauthorized, "item %d: sender does not match batch sender", i)
+        }
+        if err := create.ValidateBasic(); err != nil {
+            return sdkerrors.Wrapf(err, "item %d", i)
+        }
+    }
+    return nil
+}
+
+func (msg MsgBatchCreate) GetSigners() []sdk.AccAddress {
+    return []sdk.AccAddress{msg.Sender}
+}
+
+func NewMsgBatchClaim(claimer sdk.AccAddress, claims []MsgClaimHTLC, bestEffort bool) MsgBatchClaim {
+    return MsgBatchClaim{
+        Claimer:    claimer,
+        Claims:     claims,
+        BestEffort: bestEffort,
+    }
+}
+
+func (msg MsgBatchClaim) Route() string { return "htlc" }
+
+func (msg MsgBatchClaim) Type() string { return "batch_claim_htlc" }
+
+func (msg MsgBatchClaim) ValidateBasic() error {
+    if msg.Claimer.Empty() {
+        return sdk.ErrInvalidAddress("missing claimer address")
+    }
+    if err := validateBatchSize(len(msg.Claims)); err != nil {
+        return err
+    }
+    for i, claim := range msg.Claims {
+        if !claim.Claimer.Equals(msg.Claimer) {
+            return sdkerrors.Wrapf(sdkerrors.ErrUnauthorized, "item %d: claimer does not match batch claimer", i)
+        }
+        if err := claim.ValidateBasic(); err != nil {
+            return sdkerrors.Wrapf(err, "item %d", i)
+        }
+    }
+    return nil
+}
+
+func (msg MsgBatchClaim) GetSigners() []sdk.AccAddress {
+    return []sdk.AccAddress{msg.Claimer}
+}
+
+func NewMsgBatchRefund(sender sdk.AccAddress, refunds []MsgRefundHTLC, bestEffort bool) MsgBatchRefund {
+    return MsgBatchRefund{
+        Sender:     sender,
+        Refunds:    refunds,
+        BestEffort: bestEffort,
+    }
+}
+
+func (msg MsgBatchRefund) Route() string { return "htlc" }
+
+func (msg MsgBatchRefund) Type() string { return "batch_refund_htlc" }
+
+func (msg MsgBatchRefund) ValidateBasic() error {
+    if msg.Sender.Empty() {
+        return sdk.ErrInvalidAddress("missing sender address")
+    }
+    if err := validateBatchSize(len(msg.Refunds)); err != nil {
+        return err
+    }
+    for i, refund := range msg.Refunds {
+        if !refund.Sender.Equals(msg.Sender) {
+            return sdkerrors.Wrapf(sdkerrors.ErrUnauthorized, "item %d: sender does not match batch sender", i)
+        }
+        if err := refund.ValidateBasic(); err != nil {
+            return sdkerrors.Wrapf(err, "item %d", i)
+        }
+    }
+    return nil
+}
+
+func (msg MsgBatchRefund) GetSigners() []sdk.AccAddress {
+    return []sdk.AccAddress{msg.Sender}
+}
+
+func validateBatchSize(n int) error {
+    if n == 0 {
+        return sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "empty batch")
+    }
+    if n > MaxBatchSize {
+        return sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "batch exceeds %d items", MaxBatchSize)
+    }
+    return nil
+}
@@ -0,0 +1,78 @@
+// x/htlc/msg_relay_claim.go
+package htlc
+
+import (
+    "encoding/json"
+
+    "github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+func NewMsgRelayClaim(relayer sdk.AccAddress, id string, secret []byte, merkleProof [][]byte, fee sdk.Coins, receiverPubKey, signature []byte) MsgRelayClaim {
+    return MsgRelayClaim{
+        Relayer:        relayer,
+        ID:             id,
+        Secret:         secret,
+        MerkleProof:    merkleProof,
+        Fee:            fee,
+        ReceiverPubKey: receiverPubKey,
+        Signature:      signature,
+    }
+}
+
+func (msg MsgRelayClaim) Route() string { return "htlc" }
+
+func (msg MsgRelayClaim) Type() string { return "relay_claim_htlc" }
+
+func (msg MsgRelayClaim) ValidateBasic() error {
+    if msg.Relayer.Empty() {
+        return sdk.ErrInvalidAddress("missing relayer address")
+    }
+    if len(msg.ID) == 0 {
+        return sdkerrors.Wrap(sdkerrors.ErrUnknownRequest, "missing HTLC ID")
+    }
+    if len(msg.Secret) == 0 {
+        return sdkerrors.Wrap(sdkerrors.ErrUnknownRequest, "missing secret")
+    }
+    if err := validateSecretAndProof(msg.Secret, msg.MerkleProof); err != nil {
+        return err
+    }
+    if !msg.Fee.IsValid() {
+        return sdkerrors.Wrap(sdkerrors.ErrInvalidCoins, "invalid relayer fee")
+    }
+    if len(msg.ReceiverPubKey) == 0 {
+        return sdkerrors.Wrap(sdkerrors.ErrInvalidPubKey, "missing receiver public key")
+    }
+    if len(msg.ReceiverPubKey) != secp256k1.PubKeySize {
+        return sdkerrors.Wrapf(sdkerrors.ErrInvalidPubKey, "receiver public key must be %d bytes", secp256k1.PubKeySize)
+    }
+    if len(msg.Signature) == 0 {
+        return sdkerrors.Wrap(sdkerrors.ErrUnauthorized, "missing receiver signature")
+    }
+    return nil
+}
+
+func (msg MsgRelayClaim) GetSigners() []sdk.AccAddress {
+    return []sdk.AccAddress{msg.Relayer}
+}
+
+// RelayClaimSignBytes returns the bytes the receiver signs to authorize a
+// relayed claim. The chain ID prevents replaying the signature on another
+// chain. The secret and the HTLC's fill count at signing bind it to a single
+// fill, so a relayer can't reuse it to collect the fee on later fills of a
+// partial-fill HTLC.
+func RelayClaimSignBytes(chainID, id string, secret []byte, fee sdk.Coins, fillCount uint64, relayer sdk.AccAddress) []byte {
+    bz, err := json.Marshal(struct {
+        ChainID   string    `json:"chain_id"`
+        ID        string    `json:"id"`
+        Secret    []byte    `json:"secret"`
+        Fee       sdk.Coins `json:"fee"`
+        FillCount uint64    `json:"fill_count,string"`
+        Relayer   string    `json:"relayer"`
+    }{chainID, id, secret, fee, fillCount, relayer.String()})
+    if err != nil {
+        panic(err)
+    }
+    return sdk.MustSortJSON(bz)
+}
@@ -0,0 +1,65 @@
+// x/htlc/msg_rescue.go
+package htlc
+
+import (
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+func NewMsgRescueFunds(authority, recipient sdk.AccAddress, amount sdk.Coins) MsgRescueFunds {
+    return MsgRescueFunds{
+        Authority: authority,
+        Recipient: recipient,
+        Amount:    amount,
+    }
+}
+
+func (msg MsgRescueFunds) Route() string { return "htlc" }
+
+func (msg MsgRescueFunds) Type() string { return "rescue_funds" }
+
+func (msg MsgRescueFunds) ValidateBasic() error {
+    if msg.Authority.Empty() {
+        return sdk.ErrInvalidAddress("missing authority address")
+    }
+    if msg.Recipient.Empty() {
+        return sdk.ErrInvalidAddress("missing recipient address")
+    }
+    if !msg.Amount.IsAllPositive() {
+        return sdk.ErrInsufficientFunds("amount must be positive")
+    }
+    return nil
+}
+
+func (msg MsgRescueFunds) GetSigners() []sdk.AccAddress {
+    return []sdk.AccAddress{msg.Authority}
+}
+
+func NewMsgRescueHTLC(authority sdk.AccAddress, id string, recipient sdk.AccAddress) MsgRescueHTLC {
+    return MsgRescueHTLC{
+        Authority: authority,
+        ID:        id,
+        Recipient: recipient,
+    }
+}
+
+func (msg MsgRescueHTLC) Route() string { return "htlc" }
+
+func (msg MsgRescueHTLC) Type() string { return "rescue_htlc" }
+
+func (msg MsgRescueHTLC) ValidateBasic() error {
+    if msg.Authority.Empty() {
+        return sdk.ErrInvalidAddress("missing authority address")
+    }
+    if len(msg.ID) == 0 {
+        return sdkerrors.Wrap(sdkerrors.ErrUnknownRequest, "missing HTLC ID")
+    }
+    if msg.Recipient.Empty() {
+        return sdk.ErrInvalidAddress("missing recipient address")
+    }
+    return nil
+}
+
+func (msg MsgRescueHTLC) GetSigners() []sdk.AccAddress {
+    return []sdk.AccAddress{msg.Authority}
+}
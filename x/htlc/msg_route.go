@@ -0,0 +1,100 @@
+// x/htlc/msg_route.go
+package htlc
+
+import (
+    "time"
+
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// MaxRouteHops bounds the number of HTLCs in a route
+const MaxRouteHops = 8
+
+// MinRouteTimeLockDelta is the least time between the timelocks of
+// consecutive hops. It must cover the time a hop's sender needs to see a
+// secret revealed downstream, relay it and claim upstream before that HTLC
+// expires, so it exceeds PublicWithdrawalPeriod.
+func (p Params) MinRouteTimeLockDelta() time.Duration {
+    return 2 * p.PublicWithdrawalPeriod
+}
+
+// IsLocal reports whether the hop is an HTLC on this chain
+func (h RouteHop) IsLocal() bool {
+    return h.Chain == ""
+}
+
+func NewMsgCreateRoute(sender sdk.AccAddress, hashLock []byte, hops []RouteHop) MsgCreateRoute {
+    return MsgCreateRoute{
+        Sender:   sender,
+        HashLock: hashLock,
+        Hops:     hops,
+    }
+}
+
+func (msg MsgCreateRoute) Route() string { return "htlc" }
+
+func (msg MsgCreateRoute) Type() string { return "create_route" }
+
+func (msg MsgCreateRoute) ValidateBasic() error {
+    if msg.Sender.Empty() {
+        return sdk.ErrInvalidAddress("missing sender address")
+    }
+    if len(msg.HashLock) != HashLockLength {
+        return sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "hashlock must be %d bytes, got %d", HashLockLength, len(msg.HashLock))
+    }
+    if len(msg.Hops) < 2 || len(msg.Hops) > MaxRouteHops {
+        return sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "route must have 2 to %d hops, got %d", MaxRouteHops, len(msg.Hops))
+    }
+
+    local := -1
+    for i, hop := range msg.Hops {
+        if hop.Receiver == "" || hop.Amount == "" || hop.TimeLock == 0 {
+            return sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "hop %d: missing receiver, amount or timelock", i)
+        }
+        if len(hop.HashLock) != 0 && len(hop.HashLock) != HashLockLength {
+            return sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "hop %d: hashlock must be %d bytes", i, HashLockLength)
+        }
+        if i > 0 && hop.TimeLock >= msg.Hops[i-1].TimeLock {
+            return sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "hop %d: timelock must be below the previous hop's", i)
+        }
+        if !hop.IsLocal() {
+            continue
+        }
+        if local >= 0 {
+            return sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "hops %d and %d are both local", local, i)
+        }
+        local = i
+        if err := validateLocalHop(msg.Sender, hop); err != nil {
+            return sdkerrors.Wrapf(err, "hop %d", i)
+        }
+    }
+    if local < 0 {
+        return sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "route has no local hop")
+    }
+    return nil
+}
+
+func validateLocalHop(sender sdk.AccAddress, hop RouteHop) error {
+    if hop.Sender != "" && hop.Sender != sender.String() {
+        return sdkerrors.Wrap(sdkerrors.ErrUnauthorized, "local hop sender must be the route sender")
+    }
+    if _, err := sdk.AccAddressFromBech32(hop.Receiver); err != nil {
+        return sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, err.Error())
+    }
+    amount, err := sdk.ParseCoinsNormalized(hop.Amount)
+    if err != nil {
+        return sdkerrors.Wrap(sdkerrors.ErrInvalidCoins, err.Error())
+    }
+    if !amount.IsAllPositive() {
+        return sdk.ErrInsufficientFunds("amount must be positive")
+    }
+    if len(hop.HashLock) != 0 {
+        return sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "local hop uses the route hashlock")
+    }
+    return nil
+}
+
+func (msg MsgCreateRoute) GetSigners() []sdk.AccAddress {
+    return []sdk.AccAddress{msg.Sender}
+}
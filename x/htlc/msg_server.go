@@ -0,0 +1,109 @@
+// x/htlc/msg_server.go
+package htlc
+
+import (
+    "context"
+
+    sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+type msgServer struct {
+    keeper *Keeper
+}
+
+var _ MsgServer = msgServer{}
+
+// NewMsgServerImpl returns the htlc Msg service. It holds the keeper by
+// pointer so hooks set after the services are registered still apply.
+func NewMsgServerImpl(keeper *Keeper) MsgServer {
+    return msgServer{keeper: keeper}
+}
+
+func (s msgServer) CreateHTLC(goCtx context.Context, msg *MsgCreateHTLC) (*MsgCreateHTLCResponse, error) {
+    id, err := s.keeper.CreateHTLC(sdk.UnwrapSDKContext(goCtx), *msg)
+    if err != nil {
+        return nil, err
+    }
+    return &MsgCreateHTLCResponse{ID: id}, nil
+}
+
+func (s msgServer) ClaimHTLC(goCtx context.Context, msg *MsgClaimHTLC) (*MsgClaimHTLCResponse, error) {
+    if err := s.keeper.ClaimHTLC(sdk.UnwrapSDKContext(goCtx), *msg); err != nil {
+        return nil, err
+    }
+    return &MsgClaimHTLCResponse{}, nil
+}
+
+func (s msgServer) RefundHTLC(goCtx context.Context, msg *MsgRefundHTLC) (*MsgRefundHTLCResponse, error) {
+    if err := s.keeper.RefundHTLC(sdk.UnwrapSDKContext(goCtx), *msg); err != nil {
+        return nil, err
+    }
+    return &MsgRefundHTLCResponse{}, nil
+}
+
+func (s msgServer) BatchCreate(goCtx context.Context, msg *MsgBatchCreate) (*MsgBatchCreateResponse, error) {
+    results, err := s.keeper.BatchCreate(sdk.UnwrapSDKContext(goCtx), *msg)
+    if err != nil {
+        return nil, err
+    }
+    return &MsgBatchCreateResponse{Results: results}, nil
+}
+
+func (s msgServer) BatchClaim(goCtx context.Context, msg *MsgBatchClaim) (*MsgBatchClaimResponse, error) {
+    results, err := s.keeper.BatchClaim(sdk.UnwrapSDKContext(goCtx), *msg)
+    if err != nil {
+        return nil, err
+    }
+    return &MsgBatchClaimResponse{Results: results}, nil
+}
+
+func (s msgServer) BatchRefund(goCtx context.Context, msg *MsgBatchRefund) (*MsgBatchRefundResponse, error) {
+    results, err := s.keeper.BatchRefund(sdk.UnwrapSDKContext(goCtx), *msg)
+    if err != nil {
+        return nil, err
+    }
+    return &MsgBatchRefundResponse{Results: results}, nil
+}
+
+func (s msgServer) RescueFunds(goCtx context.Context, msg *MsgRescueFunds) (*MsgRescueFundsResponse, error) {
+    if err := s.keeper.RescueFunds(sdk.UnwrapSDKContext(goCtx), *msg); err != nil {
+        return nil, err
+    }
+    return &MsgRescueFundsResponse{}, nil
+}
+
+func (s msgServer) RescueHTLC(goCtx context.Context, msg *MsgRescueHTLC) (*MsgRescueHTLCResponse, error) {
+    if err := s.keeper.RescueHTLC(sdk.UnwrapSDKContext(goCtx), *msg); err != nil {
+        return nil, err
+    }
+    return &MsgRescueHTLCResponse{}, nil
+}
+
+func (s msgServer) RelayClaim(goCtx context.Context, msg *MsgRelayClaim) (*MsgRelayClaimResponse, error) {
+    if err := s.keeper.RelayClaim(sdk.UnwrapSDKContext(goCtx), *msg); err != nil {
+        return nil, err
+    }
+    return &MsgRelayClaimResponse{}, nil
+}
+
+func (s msgServer) VerifyEscrow(goCtx context.Context, msg *MsgVerifyEscrow) (*MsgVerifyEscrowResponse, error) {
+    if err := s.keeper.VerifyEscrow(sdk.UnwrapSDKContext(goCtx), *msg); err != nil {
+        return nil, err
+    }
+    return &MsgVerifyEscrowResponse{}, nil
+}
+
+func (s msgServer) CreateRoute(goCtx context.Context, msg *MsgCreateRoute) (*MsgCreateRouteResponse, error) {
+    id, err := s.keeper.CreateRoute(sdk.UnwrapSDKContext(goCtx), *msg)
+    if err != nil {
+        return nil, err
+    }
+    return &MsgCreateRouteResponse{ID: id}, nil
+}
+
+func (s msgServer) UpdateParams(goCtx context.Context, msg *MsgUpdateParams) (*MsgUpdateParamsResponse, error) {
+    if err := s.keeper.UpdateParams(sdk.UnwrapSDKContext(goCtx), *msg); err != nil {
+        return nil, err
+    }
+    return &MsgUpdateParamsResponse{}, nil
+}
@@ -0,0 +1,67 @@
+// x/htlc/msg_verify_escrow.go
+package htlc
+
+import (
+    "github.com/ethereum/go-ethereum/common"
+
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// MaxEthProofNodes bounds the trie proofs of MsgVerifyEscrow, well above the
+// depth of receipt and state tries
+const MaxEthProofNodes = 64
+
+func (t EscrowTerms) ValidateBasic() error {
+    if !common.IsHexAddress(t.Factory) {
+        return sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "invalid escrow factory address %q", t.Factory)
+    }
+    if len(t.HashLock) != HashLockLength {
+        return sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "escrow hashlock must be %d bytes, got %d", HashLockLength, len(t.HashLock))
+    }
+    if !common.IsHexAddress(t.Token) {
+        return sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "invalid escrow token address %q", t.Token)
+    }
+    if t.Amount.IsNil() || !t.Amount.IsPositive() {
+        return sdk.ErrInsufficientFunds("escrow amount must be positive")
+    }
+    return nil
+}
+
+func NewMsgVerifyEscrow(submitter sdk.AccAddress, id string, blockHash []byte, txIndex uint64, receiptProof, accountProof [][]byte) MsgVerifyEscrow {
+    return MsgVerifyEscrow{
+        Submitter:    submitter,
+        ID:           id,
+        BlockHash:    blockHash,
+        TxIndex:      txIndex,
+        ReceiptProof: receiptProof,
+        AccountProof: accountProof,
+    }
+}
+
+func (msg MsgVerifyEscrow) Route() string { return "htlc" }
+
+func (msg MsgVerifyEscrow) Type() string { return "verify_escrow" }
+
+func (msg MsgVerifyEscrow) ValidateBasic() error {
+    if msg.Submitter.Empty() {
+        return sdk.ErrInvalidAddress("missing submitter address")
+    }
+    if len(msg.ID) == 0 {
+        return sdkerrors.Wrap(sdkerrors.ErrUnknownRequest, "missing HTLC ID")
+    }
+    if len(msg.BlockHash) != common.HashLength {
+        return sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "block hash must be %d bytes, got %d", common.HashLength, len(msg.BlockHash))
+    }
+    if len(msg.ReceiptProof) == 0 {
+        return sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "missing receipt proof")
+    }
+    if len(msg.ReceiptProof) > MaxEthProofNodes || len(msg.AccountProof) > MaxEthProofNodes {
+        return sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "proofs are limited to %d nodes", MaxEthProofNodes)
+    }
+    return nil
+}
+
+func (msg MsgVerifyEscrow) GetSigners() []sdk.AccAddress {
+    return []sdk.AccAddress{msg.Submitter}
+}
@@ -2,18 +2,25 @@
 package htlc
 
 import (
+    "github.com/ethereum/go-ethereum/common"
+
     sdk "github.com/cosmos/cosmos-sdk/types"
+    sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
 )
 
-type MsgCreateHTLC struct {
-    Sender        sdk.AccAddress
-    Receiver      sdk.AccAddress
-    Amount        sdk.Coins
-    HashLock      []byte
-    TimeLock      uint64
-    ExternalChain string // e.g., "ethereum"
-    ExternalID    string // ID of corresponding HTLC on external chain
-}
+const (
+    // HashLockLength is the size of a sha256 hashlock
+    HashLockLength = 32
+    // SecretLength matches the bytes32 secrets used by the EVM escrows
+    SecretLength = 32
+    // MaxMerkleProofDepth bounds partial-fill proofs, enough for 2^32 secrets
+    MaxMerkleProofDepth = 32
+    // MaxParts is the most secrets a partial-fill tree of MaxMerkleProofDepth holds
+    MaxParts = 1 << MaxMerkleProofDepth
+    // MaxExternalFieldLength bounds ExternalChain and ExternalID, which are
+    // length-prefixed with a single byte in the external ID index
+    MaxExternalFieldLength = 255
+)
 
 func NewMsgCreateHTLC(sender, receiver sdk.AccAddress, amount sdk.Coins, hashLock []byte, timeLock uint64, externalChain, externalID string) MsgCreateHTLC {
     return MsgCreateHTLC{
@@ -41,6 +48,48 @@ func (msg MsgCreateHTLC) ValidateBasic() error {
     if !msg.Amount.IsAllPositive() {
         return sdk.ErrInsufficientFunds("amount must be positive")
     }
+    if len(msg.HashLock) != HashLockLength {
+        return sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "hashlock must be %d bytes, got %d", HashLockLength, len(msg.HashLock))
+    }
+    if msg.TimeLock == 0 {
+        return sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "missing timelock")
+    }
+    if msg.ExternalID != "" && msg.ExternalChain == "" {
+        return sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "external ID set without external chain")
+    }
+    if len(msg.ExternalChain) > MaxExternalFieldLength || len(msg.ExternalID) > MaxExternalFieldLength {
+        return sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "external chain and ID must be at most %d bytes", MaxExternalFieldLength)
+    }
+    if msg.Parts > 0 {
+        if msg.Parts < 2 || msg.Parts > MaxParts {
+            return sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "parts must be between 2 and %d, got %d", uint64(MaxParts), msg.Parts)
+        }
+        // Every fill has to release something
+        for _, coin := range msg.Amount {
+            if coin.Amount.LT(sdk.NewIntFromUint64(msg.Parts)) {
+                return sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "%s can't be split into %d parts", coin, msg.Parts)
+            }
+        }
+    }
+    if len(msg.FeeAllowance) > 0 && !msg.FeeAllowance.IsAllPositive() {
+        return sdk.ErrInsufficientFunds("fee allowance must be positive")
+    }
+    if msg.EscrowTerms != nil {
+        if msg.ExternalChain == "" || !common.IsHexAddress(msg.ExternalID) {
+            return sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "escrow terms require an external chain and an escrow address as external ID")
+        }
+        if err := msg.EscrowTerms.ValidateBasic(); err != nil {
+            return err
+        }
+    }
+    if msg.EscrowImmutables != nil {
+        if !common.IsHexAddress(msg.ExternalID) {
+            return sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "escrow immutables require an escrow address as external ID")
+        }
+        if err := msg.EscrowImmutables.ValidateBasic(); err != nil {
+            return err
+        }
+    }
     return nil
 }
 
@@ -48,19 +97,13 @@ func (msg MsgCreateHTLC) GetSigners() []sdk.AccAddress {
     return []sdk.AccAddress{msg.Sender}
 }
 
-type MsgClaimHTLC struct {
-    Claimer     sdk.AccAddress
-    ID          string
-    Secret      []byte
-    MerkleProof [][]byte
-}
-
-func NewMsgClaimHTLC(claimer sdk.AccAddress, id string, secret []byte, merkleProof [][]byte) MsgClaimHTLC {
+func NewMsgClaimHTLC(claimer sdk.AccAddress, id string, secret []byte, merkleProof [][]byte, target sdk.AccAddress) MsgClaimHTLC {
     return MsgClaimHTLC{
         Claimer:    claimer,
         ID:         id,
         Secret:     secret,
         MerkleProof: merkleProof,
+        Target:     target,
     }
 }
 
@@ -78,6 +121,14 @@ func (msg MsgClaimHTLC) ValidateBasic() error {
     if len(msg.Secret) == 0 {
         return sdkerrors.Wrap(sdkerrors.ErrUnknownRequest, "missing secret")
     }
+    if err := validateSecretAndProof(msg.Secret, msg.MerkleProof); err != nil {
+        return err
+    }
+    if len(msg.Target) > 0 {
+        if err := sdk.VerifyAddressFormat(msg.Target); err != nil {
+            return sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, "invalid target address")
+        }
+    }
     return nil
 }
 
@@ -85,11 +136,6 @@ func (msg MsgClaimHTLC) GetSigners() []sdk.AccAddress {
     return []sdk.AccAddress{msg.Claimer}
 }
 
-type MsgRefundHTLC struct {
-    Sender sdk.AccAddress
-    ID     string
-}
-
 func NewMsgRefundHTLC(sender sdk.AccAddress, id string) MsgRefundHTLC {
     return MsgRefundHTLC{
         Sender: sender,
@@ -114,3 +160,19 @@ func (msg MsgRefundHTLC) ValidateBasic() error {
 func (msg MsgRefundHTLC) GetSigners() []sdk.AccAddress {
     return []sdk.AccAddress{msg.Sender}
 }
+
+// validateSecretAndProof checks the secret size and the shape of a partial-fill Merkle proof
+func validateSecretAndProof(secret []byte, proof [][]byte) error {
+    if len(secret) != SecretLength {
+        return sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "secret must be %d bytes, got %d", SecretLength, len(secret))
+    }
+    if len(proof) > MaxMerkleProofDepth {
+        return sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "merkle proof depth %d exceeds %d", len(proof), MaxMerkleProofDepth)
+    }
+    for i, node := range proof {
+        if len(node) != HashLockLength {
+            return sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "merkle proof node %d must be %d bytes, got %d", i, HashLockLength, len(node))
+        }
+    }
+    return nil
+}
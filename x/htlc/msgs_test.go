@@ -0,0 +1,307 @@
+// x/htlc/msgs_test.go
+package htlc_test
+
+import (
+    "bytes"
+    "encoding/hex"
+    "math/big"
+    "strings"
+    "testing"
+    "time"
+
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    "github.com/ethereum/go-ethereum/common"
+    "github.com/ethereum/go-ethereum/crypto"
+    "github.com/stretchr/testify/require"
+
+    "github.com/your_repo/x/htlc"
+)
+
+func TestMsgCreateHTLC_ValidateBasic(t *testing.T) {
+    sender := sdk.AccAddress([]byte("sender____________"))
+    receiver := sdk.AccAddress([]byte("receiver__________"))
+    valid := htlc.MsgCreateHTLC{
+        Sender:   sender,
+        Receiver: receiver,
+        Amount:   sdk.NewCoins(sdk.NewInt64Coin("atom", 100)),
+        HashLock: sdk.Sha256([]byte("secret")),
+        TimeLock: 1_700_000_000,
+    }
+
+    cases := []struct {
+        name     string
+        malleate func(msg *htlc.MsgCreateHTLC)
+        errMsg   string
+    }{
+        {"valid", func(msg *htlc.MsgCreateHTLC) {}, ""},
+        {"missing sender", func(msg *htlc.MsgCreateHTLC) { msg.Sender = nil }, "missing sender address"},
+        {"missing receiver", func(msg *htlc.MsgCreateHTLC) { msg.Receiver = nil }, "missing receiver address"},
+        {"zero amount", func(msg *htlc.MsgCreateHTLC) { msg.Amount = sdk.Coins{} }, "amount must be positive"},
+        {"short hashlock", func(msg *htlc.MsgCreateHTLC) { msg.HashLock = []byte("short") }, "hashlock must be 32 bytes"},
+        {"missing timelock", func(msg *htlc.MsgCreateHTLC) { msg.TimeLock = 0 }, "missing timelock"},
+        {"external ID without chain", func(msg *htlc.MsgCreateHTLC) { msg.ExternalID = "0xescrow" }, "external ID set without external chain"},
+        {"external chain too long", func(msg *htlc.MsgCreateHTLC) {
+            msg.ExternalChain = strings.Repeat("c", htlc.MaxExternalFieldLength+1)
+        }, "external chain and ID must be at most 255 bytes"},
+        {"external ID too long", func(msg *htlc.MsgCreateHTLC) {
+            msg.ExternalChain, msg.ExternalID = "ethereum", strings.Repeat("i", htlc.MaxExternalFieldLength+1)
+        }, "external chain and ID must be at most 255 bytes"},
+        {"longest external ID", func(msg *htlc.MsgCreateHTLC) {
+            msg.ExternalChain, msg.ExternalID = "ethereum", strings.Repeat("i", htlc.MaxExternalFieldLength)
+        }, ""},
+        {"invalid fee allowance", func(msg *htlc.MsgCreateHTLC) {
+            msg.FeeAllowance = sdk.Coins{sdk.Coin{Denom: "atom", Amount: sdk.ZeroInt()}}
+        }, "fee allowance must be positive"},
+        {"partial fill", func(msg *htlc.MsgCreateHTLC) { msg.Parts = 100 }, ""},
+        {"single part", func(msg *htlc.MsgCreateHTLC) { msg.Parts = 1 }, "parts must be between 2 and 4294967296"},
+        {"too many parts", func(msg *htlc.MsgCreateHTLC) { msg.Parts = htlc.MaxParts + 1 }, "parts must be between 2 and 4294967296"},
+        {"part below one unit", func(msg *htlc.MsgCreateHTLC) { msg.Parts = 101 }, "100atom can't be split into 101 parts"},
+    }
+
+    for _, tc := range cases {
+        t.Run(tc.name, func(t *testing.T) {
+            msg := valid
+            tc.malleate(&msg)
+            err := msg.ValidateBasic()
+            if tc.errMsg == "" {
+                require.NoError(t, err)
+                return
+            }
+            require.ErrorContains(t, err, tc.errMsg)
+        })
+    }
+}
+
+func TestMsgClaimHTLC_ValidateBasic(t *testing.T) {
+    receiver := sdk.AccAddress([]byte("receiver__________"))
+    secret := bytes.Repeat([]byte{0x01}, htlc.SecretLength)
+    node := bytes.Repeat([]byte{0x02}, htlc.HashLockLength)
+    valid := htlc.MsgClaimHTLC{
+        Claimer:     receiver,
+        ID:          "id",
+        Secret:      secret,
+        MerkleProof: [][]byte{node},
+    }
+
+    cases := []struct {
+        name     string
+        malleate func(msg *htlc.MsgClaimHTLC)
+        errMsg   string
+    }{
+        {"valid", func(msg *htlc.MsgClaimHTLC) {}, ""},
+        {"missing claimer", func(msg *htlc.MsgClaimHTLC) { msg.Claimer = nil }, "missing claimer address"},
+        {"missing ID", func(msg *htlc.MsgClaimHTLC) { msg.ID = "" }, "missing HTLC ID"},
+        {"missing secret", func(msg *htlc.MsgClaimHTLC) { msg.Secret = nil }, "missing secret"},
+        {"short secret", func(msg *htlc.MsgClaimHTLC) { msg.Secret = []byte("secret") }, "secret must be 32 bytes"},
+        {"proof too deep", func(msg *htlc.MsgClaimHTLC) {
+            msg.MerkleProof = make([][]byte, htlc.MaxMerkleProofDepth+1)
+            for i := range msg.MerkleProof {
+                msg.MerkleProof[i] = node
+            }
+        }, "merkle proof depth"},
+        {"short proof node", func(msg *htlc.MsgClaimHTLC) { msg.MerkleProof = [][]byte{node[:16]} }, "merkle proof node 0 must be 32 bytes"},
+    }
+
+    for _, tc := range cases {
+        t.Run(tc.name, func(t *testing.T) {
+            msg := valid
+            tc.malleate(&msg)
+            err := msg.ValidateBasic()
+            if tc.errMsg == "" {
+                require.NoError(t, err)
+                return
+            }
+            require.ErrorContains(t, err, tc.errMsg)
+        })
+    }
+}
+
+func TestParams_ValidateCreate(t *testing.T) {
+    now := time.Unix(1_700_000_000, 0)
+    params := htlc.DefaultParams()
+
+    cases := []struct {
+        name     string
+        timeLock time.Time
+        chain    string
+        errMsg   string
+    }{
+        {"valid", now.Add(2 * time.Hour), "ethereum", ""},
+        {"no external chain", now.Add(2 * time.Hour), "", ""},
+        {"at minimum", now.Add(params.MinTimeLockDuration), "", ""},
+        {"in the past", now.Add(-time.Second), "", "timelock must be at least"},
+        {"below minimum", now.Add(params.MinTimeLockDuration - time.Second), "", "timelock must be at least"},
+        {"above maximum", now.Add(params.MaxTimeLockDuration + time.Second), "", "timelock must be at most"},
+        {"unregistered chain", now.Add(2 * time.Hour), "solana", "external chain solana is not registered"},
+    }
+
+    for _, tc := range cases {
+        t.Run(tc.name, func(t *testing.T) {
+            msg := htlc.MsgCreateHTLC{TimeLock: uint64(tc.timeLock.Unix()), ExternalChain: tc.chain}
+            err := params.ValidateCreate(msg, now)
+            if tc.errMsg == "" {
+                require.NoError(t, err)
+                return
+            }
+            require.ErrorContains(t, err, tc.errMsg)
+        })
+    }
+}
+
+func TestParams_Validate_PublicStages(t *testing.T) {
+    params := htlc.DefaultParams()
+    params.PublicWithdrawalPeriod = 0
+    require.ErrorContains(t, params.Validate(), "public withdrawal period must be positive")
+
+    params = htlc.DefaultParams()
+    params.PublicCancellationDelay = -time.Second
+    require.ErrorContains(t, params.Validate(), "public cancellation delay must be positive")
+
+    // The public stage can't start before an HTLC at the minimum timelock exists
+    params = htlc.DefaultParams()
+    params.PublicWithdrawalPeriod = params.MinTimeLockDuration
+    require.ErrorContains(t, params.Validate(), "public withdrawal period must be below the min timelock duration")
+    params.PublicWithdrawalPeriod = params.MinTimeLockDuration - time.Second
+    require.NoError(t, params.Validate())
+}
+
+func TestParams_ValidateCreate_EscrowAddress(t *testing.T) {
+    now := time.Unix(1_700_000_000, 0)
+    factory := htlc.EscrowFactory{
+        Chain:             "ethereum",
+        Address:           "0x00000000000000000000000000000000000000fa",
+        SrcImplementation: "0x00000000000000000000000000000000000000a1",
+        DstImplementation: "0x00000000000000000000000000000000000000a2",
+    }
+    params := htlc.DefaultParams()
+    params.EscrowFactories = []htlc.EscrowFactory{factory}
+    require.NoError(t, params.Validate())
+
+    immutables := htlc.EscrowImmutables{
+        OrderHash:     crypto.Keccak256([]byte("order")),
+        HashLock:      crypto.Keccak256([]byte("secret")),
+        Maker:         "0x0000000000000000000000000000000000000001",
+        Taker:         "0x0000000000000000000000000000000000000002",
+        Token:         "0x00000000000000000000000000000000000000c0",
+        Amount:        sdk.NewInt(500),
+        SafetyDeposit: sdk.NewInt(10),
+        Timelocks:     sdk.NewInt(now.Unix()),
+    }
+    src, dst := factory.EscrowAddresses(immutables)
+    require.NotEqual(t, src, dst)
+    otherAmount := immutables
+    otherAmount.Amount = sdk.NewInt(501)
+    terms := func(amount int64) *htlc.EscrowTerms {
+        return &htlc.EscrowTerms{Factory: factory.Address, HashLock: immutables.HashLock, Token: immutables.Token, Amount: sdk.NewInt(amount)}
+    }
+
+    cases := []struct {
+        name       string
+        externalID string
+        immutables *htlc.EscrowImmutables
+        terms      *htlc.EscrowTerms
+        errMsg     string
+    }{
+        {"source escrow", src.Hex(), &immutables, nil, ""},
+        {"destination escrow", dst.Hex(), &immutables, nil, ""},
+        {"matching terms", src.Hex(), &immutables, terms(500), ""},
+        {"no counterpart yet", "", nil, nil, ""},
+        {"not an escrow address", "order-1", nil, nil, "is not an escrow address"},
+        {"missing immutables", src.Hex(), nil, nil, "escrow immutables required"},
+        {"typo in address", common.BytesToAddress(append(src.Bytes()[:19], src.Bytes()[19]^1)).Hex(), &immutables, nil, "is not deployed by factory"},
+        {"spoofed amount", src.Hex(), &otherAmount, nil, "is not deployed by factory"},
+        {"terms amount differs", src.Hex(), &immutables, terms(501), "amounts differ"},
+    }
+
+    for _, tc := range cases {
+        t.Run(tc.name, func(t *testing.T) {
+            msg := htlc.MsgCreateHTLC{
+                TimeLock:         uint64(now.Add(2 * time.Hour).Unix()),
+                ExternalChain:    "ethereum",
+                ExternalID:       tc.externalID,
+                EscrowImmutables: tc.immutables,
+                EscrowTerms:      tc.terms,
+            }
+            err := params.ValidateCreate(msg, now)
+            if tc.errMsg == "" {
+                require.NoError(t, err)
+                return
+            }
+            require.ErrorIs(t, err, htlc.ErrEscrowMismatch)
+            require.ErrorContains(t, err, tc.errMsg)
+        })
+    }
+
+    params.EscrowFactories = append(params.EscrowFactories, htlc.EscrowFactory{Chain: "solana", Address: factory.Address, SrcImplementation: factory.SrcImplementation, DstImplementation: factory.DstImplementation})
+    require.ErrorContains(t, params.Validate(), "unregistered external chain solana")
+}
+
+// TestEscrowAddresses_KnownAnswer pins the CREATE2 derivation to values
+// computed independently from the contracts: ImmutablesLib.hash over the 8
+// ABI words, ProxyHashLib.computeProxyBytecodeHash's 55 bytes at memory 0x09,
+// and Create2.computeAddress with the factory as deployer, i.e. what
+// BaseEscrowFactory.addressOfEscrowSrc and addressOfEscrowDst return. Check
+// with `cast create2 --deployer <factory> --salt <salt> --init-code-hash <hash>`.
+func TestEscrowAddresses_KnownAnswer(t *testing.T) {
+    factory := htlc.EscrowFactory{
+        Chain:             "ethereum",
+        Address:           "0x1111111111111111111111111111111111111111",
+        SrcImplementation: "0x2222222222222222222222222222222222222222",
+        DstImplementation: "0x3333333333333333333333333333333333333333",
+    }
+    // deployedAt in the top 32 bits, two stage offsets below
+    timelocks := new(big.Int).Lsh(big.NewInt(1_700_000_000), 224)
+    timelocks.Or(timelocks, big.NewInt(3600<<32|7200))
+    immutables := htlc.EscrowImmutables{
+        OrderHash:     crypto.Keccak256([]byte("order")),
+        HashLock:      crypto.Keccak256(bytes.Repeat([]byte{0x01}, 32)),
+        Maker:         "0x00000000000000000000000000000000000000aa",
+        Taker:         "0x00000000000000000000000000000000000000bb",
+        Token:         "0x00000000000000000000000000000000000000cc",
+        Amount:        sdk.NewIntFromBigInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)),
+        SafetyDeposit: sdk.NewIntFromBigInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(16), nil)),
+        Timelocks:     sdk.NewIntFromBigInt(timelocks),
+    }
+    require.NoError(t, immutables.ValidateBasic())
+
+    require.Equal(t, "ed2d84be3bfad0d8827bb7cb018a0aefc9cdce1251fce1631047d0830bbcb890", hex.EncodeToString(immutables.Hash()))
+    require.Equal(t, "100119e6c2c500692ce3d11bb1218d751a6e4343d712634c8735d3f40f32f3f2", hex.EncodeToString(htlc.ProxyBytecodeHash(common.HexToAddress(factory.SrcImplementation))))
+    require.Equal(t, "12a532ab338471c8a9fed095302ec30b622c22daf82017624040694f03bd1f8b", hex.EncodeToString(htlc.ProxyBytecodeHash(common.HexToAddress(factory.DstImplementation))))
+
+    src, dst := factory.EscrowAddresses(immutables)
+    require.Equal(t, common.HexToAddress("0x5232a6477506d3eebb116e8fb34d8d04c537de85"), src)
+    require.Equal(t, common.HexToAddress("0x496b2de0dfab9a9d4f9840a1d12ff09a59c9c2be"), dst)
+}
+
+func TestMsgCreateRoute_ValidateBasic(t *testing.T) {
+    sender := sdk.AccAddress([]byte("sender____________"))
+    receiver := sdk.AccAddress([]byte("receiver__________"))
+    local := htlc.RouteHop{Receiver: receiver.String(), Amount: "100atom", TimeLock: 2000}
+    remote := htlc.RouteHop{Chain: "ethereum", Sender: "0x01", Receiver: "0x02", Amount: "5", TimeLock: 1000}
+    hashLock := bytes.Repeat([]byte{1}, htlc.HashLockLength)
+
+    cases := []struct {
+        name   string
+        hops   []htlc.RouteHop
+        errMsg string
+    }{
+        {"valid", []htlc.RouteHop{local, remote}, ""},
+        {"single hop", []htlc.RouteHop{local}, "route must have 2 to"},
+        {"no local hop", []htlc.RouteHop{{Chain: "osmosis", Receiver: "osmo1", Amount: "1", TimeLock: 3000}, remote}, "no local hop"},
+        {"two local hops", []htlc.RouteHop{{Receiver: receiver.String(), Amount: "1atom", TimeLock: 3000}, local}, "both local"},
+        {"increasing timelocks", []htlc.RouteHop{remote, local}, "timelock must be below"},
+        {"local hop for someone else", []htlc.RouteHop{{Sender: receiver.String(), Receiver: receiver.String(), Amount: "1atom", TimeLock: 2000}, remote}, "route sender"},
+        {"local hop amount", []htlc.RouteHop{{Receiver: receiver.String(), Amount: "atom", TimeLock: 2000}, remote}, "hop 0"},
+    }
+
+    for _, tc := range cases {
+        t.Run(tc.name, func(t *testing.T) {
+            err := htlc.NewMsgCreateRoute(sender, hashLock, tc.hops).ValidateBasic()
+            if tc.errMsg == "" {
+                require.NoError(t, err)
+                return
+            }
+            require.ErrorContains(t, err, tc.errMsg)
+        })
+    }
+}
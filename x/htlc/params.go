@@ -0,0 +1,173 @@
+// x/htlc/params.go
+package htlc
+
+import (
+    "time"
+
+    "github.com/ethereum/go-ethereum/common"
+
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// Store key for the module parameters
+var ParamsKey = []byte{0x07}
+
+const (
+    // DefaultRevealedSecretRetention is about a week of 6 second blocks
+    DefaultRevealedSecretRetention = 100800
+    // DefaultMinTimeLockDuration leaves the receiver a private window at
+    // least as long as the public withdrawal period
+    DefaultMinTimeLockDuration = 2 * DefaultPublicWithdrawalPeriod
+    DefaultMaxTimeLockDuration = 30 * 24 * time.Hour
+    // The public stages default to those of the EVM escrows
+    DefaultPublicWithdrawalPeriod  = time.Hour
+    DefaultPublicCancellationDelay = 24 * time.Hour
+)
+
+func NewParams(enforceUniqueHashLock bool, revealedSecretRetention uint64, minTimeLock, maxTimeLock, publicWithdrawal, publicCancellation time.Duration, externalChains []string) Params {
+    return Params{
+        EnforceUniqueHashLock:   enforceUniqueHashLock,
+        RevealedSecretRetention: revealedSecretRetention,
+        MinTimeLockDuration:     minTimeLock,
+        MaxTimeLockDuration:     maxTimeLock,
+        PublicWithdrawalPeriod:  publicWithdrawal,
+        PublicCancellationDelay: publicCancellation,
+        ExternalChains:          externalChains,
+    }
+}
+
+func DefaultParams() Params {
+    return NewParams(true, DefaultRevealedSecretRetention, DefaultMinTimeLockDuration, DefaultMaxTimeLockDuration, DefaultPublicWithdrawalPeriod, DefaultPublicCancellationDelay, []string{"ethereum"})
+}
+
+func (p Params) Validate() error {
+    if p.MinTimeLockDuration <= 0 {
+        return sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "min timelock duration must be positive")
+    }
+    if p.MaxTimeLockDuration < p.MinTimeLockDuration {
+        return sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "max timelock duration is below the minimum")
+    }
+    if p.PublicWithdrawalPeriod <= 0 {
+        return sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "public withdrawal period must be positive")
+    }
+    // Otherwise an HTLC at the minimum timelock is public from its creation
+    // and the receiver never gets to claim alone
+    if p.PublicWithdrawalPeriod >= p.MinTimeLockDuration {
+        return sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "public withdrawal period must be below the min timelock duration")
+    }
+    if p.PublicCancellationDelay <= 0 {
+        return sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "public cancellation delay must be positive")
+    }
+    seen := make(map[string]bool, len(p.ExternalChains))
+    for _, chain := range p.ExternalChains {
+        if chain == "" {
+            return sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "empty external chain name")
+        }
+        if seen[chain] {
+            return sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "duplicate external chain %s", chain)
+        }
+        seen[chain] = true
+    }
+    factories := make(map[string]bool, len(p.EscrowFactories))
+    for _, factory := range p.EscrowFactories {
+        if !seen[factory.Chain] {
+            return sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "escrow factory for unregistered external chain %s", factory.Chain)
+        }
+        if factories[factory.Chain] {
+            return sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "duplicate escrow factory for %s", factory.Chain)
+        }
+        factories[factory.Chain] = true
+        if err := factory.Validate(); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// EscrowFactory returns the escrow factory registered for chain
+func (p Params) EscrowFactory(chain string) (EscrowFactory, bool) {
+    for _, factory := range p.EscrowFactories {
+        if factory.Chain == chain {
+            return factory, true
+        }
+    }
+    return EscrowFactory{}, false
+}
+
+// ValidateCreate checks the parts of msg that depend on the parameters: the
+// TimeLock must fall within the allowed window after now, ExternalChain, when
+// set, must be registered, and an escrow ExternalID on a chain with an escrow
+// factory must be the escrow's CREATE2 address
+func (p Params) ValidateCreate(msg MsgCreateHTLC, now time.Time) error {
+    timeLock := time.Unix(int64(msg.TimeLock), 0)
+    if timeLock.Before(now.Add(p.MinTimeLockDuration)) {
+        return sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "timelock must be at least %s in the future", p.MinTimeLockDuration)
+    }
+    if timeLock.After(now.Add(p.MaxTimeLockDuration)) {
+        return sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "timelock must be at most %s in the future", p.MaxTimeLockDuration)
+    }
+    if msg.ExternalChain != "" && !containsString(p.ExternalChains, msg.ExternalChain) {
+        return sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "external chain %s is not registered", msg.ExternalChain)
+    }
+    if factory, found := p.EscrowFactory(msg.ExternalChain); found && msg.ExternalID != "" {
+        // Counterparts on a chain with a registered factory are its escrows
+        if !common.IsHexAddress(msg.ExternalID) {
+            return sdkerrors.Wrapf(ErrEscrowMismatch, "external ID %q is not an escrow address on %s", msg.ExternalID, msg.ExternalChain)
+        }
+        return validateEscrowAddress(msg, factory)
+    }
+    return nil
+}
+
+// GetParams returns the module parameters, or the defaults if none were set
+func (k Keeper) GetParams(ctx sdk.Context) Params {
+    bz := ctx.KVStore(k.storeKey).Get(ParamsKey)
+    if bz == nil {
+        return DefaultParams()
+    }
+    var params Params
+    k.cdc.MustUnmarshal(bz, &params)
+    return params
+}
+
+func (k Keeper) SetParams(ctx sdk.Context, params Params) error {
+    if err := params.Validate(); err != nil {
+        return err
+    }
+    bz, err := k.cdc.Marshal(&params)
+    if err != nil {
+        return err
+    }
+    ctx.KVStore(k.storeKey).Set(ParamsKey, bz)
+    return nil
+}
+
+func NewMsgUpdateParams(authority sdk.AccAddress, params Params) MsgUpdateParams {
+    return MsgUpdateParams{
+        Authority: authority,
+        Params:    params,
+    }
+}
+
+func (msg MsgUpdateParams) Route() string { return "htlc" }
+
+func (msg MsgUpdateParams) Type() string { return "update_params" }
+
+func (msg MsgUpdateParams) ValidateBasic() error {
+    if msg.Authority.Empty() {
+        return sdk.ErrInvalidAddress("missing authority address")
+    }
+    return msg.Params.Validate()
+}
+
+func (msg MsgUpdateParams) GetSigners() []sdk.AccAddress {
+    return []sdk.AccAddress{msg.Authority}
+}
+
+func (k Keeper) UpdateParams(ctx sdk.Context, msg MsgUpdateParams) error {
+    if msg.Authority.String() != k.authority {
+        return sdkerrors.Wrapf(sdkerrors.ErrUnauthorized, "expected %s as authority, got %s", k.authority, msg.Authority)
+    }
+    return k.SetParams(ctx, msg.Params)
+}
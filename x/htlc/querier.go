@@ -0,0 +1,184 @@
+// x/htlc/querier.go
+package htlc
+
+import (
+    abci "github.com/tendermint/tendermint/abci/types"
+
+    "github.com/cosmos/cosmos-sdk/codec"
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// Query endpoints supported by the htlc querier
+const (
+    QueryHTLC           = "htlc"
+    QueryStatus         = "status"
+    QueryBySender       = "by_sender"
+    QueryByReceiver     = "by_receiver"
+    QueryByHashLock     = "by_hashlock"
+    QueryByExternalID   = "by_external_id"
+    QueryHashLockUsage  = "hashlock_usage"
+    QueryRevealedSecret = "revealed_secret"
+    QueryParams         = "params"
+    QueryRoute          = "route"
+)
+
+// QueryByIDParams selects an HTLC by ID
+type QueryByIDParams struct {
+    ID string `json:"id"`
+}
+
+// QueryByAddressParams selects HTLCs by sender or receiver address
+type QueryByAddressParams struct {
+    Address sdk.AccAddress `json:"address"`
+}
+
+// QueryByHashLockParams selects HTLCs by hashlock
+type QueryByHashLockParams struct {
+    HashLock []byte `json:"hashlock"`
+}
+
+// QueryByExternalIDParams selects HTLCs by their counterpart on an external chain
+type QueryByExternalIDParams struct {
+    ExternalChain string `json:"external_chain"`
+    ExternalID    string `json:"external_id"`
+}
+
+func NewQuerier(k Keeper, legacyQuerierCdc *codec.LegacyAmino) sdk.Querier {
+    return func(ctx sdk.Context, path []string, req abci.RequestQuery) ([]byte, error) {
+        switch path[0] {
+        case QueryHTLC:
+            return queryHTLC(ctx, req, k, legacyQuerierCdc)
+        case QueryStatus:
+            return queryStatus(ctx, req, k, legacyQuerierCdc)
+        case QueryBySender, QueryByReceiver:
+            return queryByAddress(ctx, path[0], req, k, legacyQuerierCdc)
+        case QueryByHashLock:
+            return queryByHashLock(ctx, req, k, legacyQuerierCdc)
+        case QueryByExternalID:
+            return queryByExternalID(ctx, req, k, legacyQuerierCdc)
+        case QueryHashLockUsage:
+            return queryHashLockUsage(ctx, req, k, legacyQuerierCdc)
+        case QueryRevealedSecret:
+            return queryRevealedSecret(ctx, req, k, legacyQuerierCdc)
+        case QueryRoute:
+            return queryRoute(ctx, req, k, legacyQuerierCdc)
+        case QueryParams:
+            return codec.MarshalJSONIndent(legacyQuerierCdc, k.GetParams(ctx))
+        default:
+            return nil, sdkerrors.Wrapf(sdkerrors.ErrUnknownRequest, "unknown htlc query endpoint: %s", path[0])
+        }
+    }
+}
+
+func queryHTLC(ctx sdk.Context, req abci.RequestQuery, k Keeper, legacyQuerierCdc *codec.LegacyAmino) ([]byte, error) {
+    var params QueryByIDParams
+    if err := legacyQuerierCdc.UnmarshalJSON(req.Data, &params); err != nil {
+        return nil, sdkerrors.Wrap(sdkerrors.ErrJSONUnmarshal, err.Error())
+    }
+
+    htlc, err := k.GetHTLC(ctx, params.ID)
+    if err != nil {
+        return nil, err
+    }
+    return codec.MarshalJSONIndent(legacyQuerierCdc, htlc)
+}
+
+func queryStatus(ctx sdk.Context, req abci.RequestQuery, k Keeper, legacyQuerierCdc *codec.LegacyAmino) ([]byte, error) {
+    var params QueryByIDParams
+    if err := legacyQuerierCdc.UnmarshalJSON(req.Data, &params); err != nil {
+        return nil, sdkerrors.Wrap(sdkerrors.ErrJSONUnmarshal, err.Error())
+    }
+
+    status, err := k.HTLCStatus(ctx, params.ID)
+    if err != nil {
+        return nil, err
+    }
+    return codec.MarshalJSONIndent(legacyQuerierCdc, QueryStatusResponse{ID: params.ID, Status: status.String()})
+}
+
+func queryByAddress(ctx sdk.Context, endpoint string, req abci.RequestQuery, k Keeper, legacyQuerierCdc *codec.LegacyAmino) ([]byte, error) {
+    var params QueryByAddressParams
+    if err := legacyQuerierCdc.UnmarshalJSON(req.Data, &params); err != nil {
+        return nil, sdkerrors.Wrap(sdkerrors.ErrJSONUnmarshal, err.Error())
+    }
+
+    var (
+        htlcs []HTLC
+        err   error
+    )
+    if endpoint == QueryBySender {
+        htlcs, err = k.GetHTLCsBySender(ctx, params.Address)
+    } else {
+        htlcs, err = k.GetHTLCsByReceiver(ctx, params.Address)
+    }
+    if err != nil {
+        return nil, err
+    }
+    return codec.MarshalJSONIndent(legacyQuerierCdc, htlcs)
+}
+
+func queryByHashLock(ctx sdk.Context, req abci.RequestQuery, k Keeper, legacyQuerierCdc *codec.LegacyAmino) ([]byte, error) {
+    var params QueryByHashLockParams
+    if err := legacyQuerierCdc.UnmarshalJSON(req.Data, &params); err != nil {
+        return nil, sdkerrors.Wrap(sdkerrors.ErrJSONUnmarshal, err.Error())
+    }
+
+    htlcs, err := k.GetHTLCsByHashLock(ctx, params.HashLock)
+    if err != nil {
+        return nil, err
+    }
+    return codec.MarshalJSONIndent(legacyQuerierCdc, htlcs)
+}
+
+func queryByExternalID(ctx sdk.Context, req abci.RequestQuery, k Keeper, legacyQuerierCdc *codec.LegacyAmino) ([]byte, error) {
+    var params QueryByExternalIDParams
+    if err := legacyQuerierCdc.UnmarshalJSON(req.Data, &params); err != nil {
+        return nil, sdkerrors.Wrap(sdkerrors.ErrJSONUnmarshal, err.Error())
+    }
+
+    htlcs, err := k.GetHTLCsByExternalID(ctx, params.ExternalChain, params.ExternalID)
+    if err != nil {
+        return nil, err
+    }
+    return codec.MarshalJSONIndent(legacyQuerierCdc, htlcs)
+}
+
+func queryHashLockUsage(ctx sdk.Context, req abci.RequestQuery, k Keeper, legacyQuerierCdc *codec.LegacyAmino) ([]byte, error) {
+    var params QueryByHashLockParams
+    if err := legacyQuerierCdc.UnmarshalJSON(req.Data, &params); err != nil {
+        return nil, sdkerrors.Wrap(sdkerrors.ErrJSONUnmarshal, err.Error())
+    }
+
+    usage, err := k.GetHashLockUsage(ctx, params.HashLock)
+    if err != nil {
+        return nil, err
+    }
+    return codec.MarshalJSONIndent(legacyQuerierCdc, usage)
+}
+
+func queryRevealedSecret(ctx sdk.Context, req abci.RequestQuery, k Keeper, legacyQuerierCdc *codec.LegacyAmino) ([]byte, error) {
+    var params QueryByHashLockParams
+    if err := legacyQuerierCdc.UnmarshalJSON(req.Data, &params); err != nil {
+        return nil, sdkerrors.Wrap(sdkerrors.ErrJSONUnmarshal, err.Error())
+    }
+
+    revealed, err := k.GetRevealedSecret(ctx, params.HashLock)
+    if err != nil {
+        return nil, err
+    }
+    return codec.MarshalJSONIndent(legacyQuerierCdc, revealed)
+}
+
+func queryRoute(ctx sdk.Context, req abci.RequestQuery, k Keeper, legacyQuerierCdc *codec.LegacyAmino) ([]byte, error) {
+    var params QueryByIDParams
+    if err := legacyQuerierCdc.UnmarshalJSON(req.Data, &params); err != nil {
+        return nil, sdkerrors.Wrap(sdkerrors.ErrJSONUnmarshal, err.Error())
+    }
+
+    status, err := k.RouteStatus(ctx, params.ID)
+    if err != nil {
+        return nil, err
+    }
+    return codec.MarshalJSONIndent(legacyQuerierCdc, status)
+}
@@ -0,0 +1,103 @@
+// x/htlc/relay_claim_test.go
+package htlc_test
+
+import (
+    "bytes"
+    "time"
+
+    "github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+
+    "github.com/your_repo/x/htlc"
+    "github.com/your_repo/x/htlc/testutil"
+)
+
+// relayReceiver makes the suite receiver an account the test holds the key of
+func (s *KeeperTestSuite) relayReceiver() *secp256k1.PrivKey {
+    priv := secp256k1.GenPrivKey()
+    s.receiver = sdk.AccAddress(priv.PubKey().Address())
+    return priv
+}
+
+// relayClaim builds the claim of id through s.other, signed by priv over the
+// HTLC's current fill count
+func (s *KeeperTestSuite) relayClaim(priv *secp256k1.PrivKey, id string, secret []byte, proof [][]byte, fee sdk.Coins) htlc.MsgRelayClaim {
+    stored, err := s.keeper.GetHTLC(s.ctx, id)
+    s.Require().NoError(err)
+    signBytes := htlc.RelayClaimSignBytes(s.ctx.ChainID(), id, secret, fee, stored.FillCount, s.other)
+    sig, err := priv.Sign(signBytes)
+    s.Require().NoError(err)
+    return htlc.NewMsgRelayClaim(s.other, id, secret, proof, fee, priv.PubKey().Bytes(), sig)
+}
+
+func (s *KeeperTestSuite) TestRelayClaim_PaysRelayerFee() {
+    priv := s.relayReceiver()
+    secret := bytes.Repeat([]byte{0x01}, htlc.SecretLength)
+    created := s.CreateHTLC(secret, 2*time.Hour)
+
+    msg := s.relayClaim(priv, created.ID, secret, nil, sdk.NewCoins(sdk.NewInt64Coin("atom", 1)))
+    s.Require().NoError(msg.ValidateBasic())
+    s.Require().NoError(s.keeper.RelayClaim(s.ctx, msg))
+    s.Require().Equal(sdk.NewCoins(sdk.NewInt64Coin("atom", 99)), s.bank.GetAllBalances(s.ctx, s.receiver))
+    s.Require().Equal(sdk.NewCoins(sdk.NewInt64Coin("atom", 1)), s.bank.GetAllBalances(s.ctx, s.other))
+}
+
+func (s *KeeperTestSuite) TestRelayClaim_MalformedPubKey() {
+    priv := s.relayReceiver()
+    secret := bytes.Repeat([]byte{0x01}, htlc.SecretLength)
+    created := s.CreateHTLC(secret, 2*time.Hour)
+
+    for _, size := range []int{1, secp256k1.PubKeySize - 1, secp256k1.PubKeySize + 1, 65} {
+        msg := s.relayClaim(priv, created.ID, secret, nil, nil)
+        msg.ReceiverPubKey = bytes.Repeat([]byte{0x02}, size)
+        s.Require().ErrorIs(msg.ValidateBasic(), sdkerrors.ErrInvalidPubKey, "size %d", size)
+        // Checked again by the keeper, since an address can't be derived from it
+        s.Require().NotPanics(func() {
+            s.Require().ErrorIs(s.keeper.RelayClaim(s.ctx, msg), sdkerrors.ErrInvalidPubKey, "size %d", size)
+        })
+    }
+}
+
+func (s *KeeperTestSuite) TestRelayClaim_SignatureBoundToOneFill() {
+    priv := s.relayReceiver()
+    tree := testutil.BuildMerkleTree(testutil.NewMerkleSecrets(4))
+    created := s.CreatePartialFill(tree, 2*time.Hour)
+    var fee sdk.Coins
+
+    first := s.relayClaim(priv, created.ID, tree.Secrets[0], tree.Proofs[0], fee)
+    s.Require().NoError(s.keeper.RelayClaim(s.ctx, first))
+
+    // The relayer can't attach the signature to the next fill's secret
+    replay := first
+    replay.Secret, replay.MerkleProof = tree.Secrets[1], tree.Proofs[1]
+    s.Require().ErrorIs(s.keeper.RelayClaim(s.ctx, replay), sdkerrors.ErrUnauthorized)
+
+    // nor raise the fee it was given
+    raised := s.relayClaim(priv, created.ID, tree.Secrets[1], tree.Proofs[1], fee)
+    raised.Fee = sdk.NewCoins(sdk.NewInt64Coin("atom", 1))
+    s.Require().ErrorIs(s.keeper.RelayClaim(s.ctx, raised), sdkerrors.ErrUnauthorized)
+}
+
+func (s *KeeperTestSuite) TestRelayClaim_FeeCappedByFill() {
+    priv := s.relayReceiver()
+    tree := testutil.BuildMerkleTree(testutil.NewMerkleSecrets(4))
+    id, err := s.keeper.CreateHTLC(s.ctx, htlc.MsgCreateHTLC{
+        Sender:   s.sender,
+        Receiver: s.receiver,
+        Amount:   sdk.NewCoins(sdk.NewInt64Coin("atom", 400)),
+        HashLock: tree.Root,
+        TimeLock: uint64(s.ctx.BlockTime().Add(2 * time.Hour).Unix()),
+        Parts:    4,
+    })
+    s.Require().NoError(err)
+
+    // 1% of the 100atom this fill releases, not of the 400atom locked
+    over := s.relayClaim(priv, id, tree.Secrets[0], tree.Proofs[0], sdk.NewCoins(sdk.NewInt64Coin("atom", 2)))
+    s.Require().ErrorIs(s.keeper.RelayClaim(s.ctx, over), sdkerrors.ErrInvalidRequest)
+
+    capped := s.relayClaim(priv, id, tree.Secrets[0], tree.Proofs[0], sdk.NewCoins(sdk.NewInt64Coin("atom", 1)))
+    s.Require().NoError(s.keeper.RelayClaim(s.ctx, capped))
+    s.Require().Equal(sdk.NewCoins(sdk.NewInt64Coin("atom", 99)), s.bank.GetAllBalances(s.ctx, s.receiver))
+    s.Require().Equal(sdk.NewCoins(sdk.NewInt64Coin("atom", 1)), s.bank.GetAllBalances(s.ctx, s.other))
+}
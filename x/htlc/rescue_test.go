@@ -0,0 +1,107 @@
+// x/htlc/rescue_test.go
+package htlc_test
+
+import (
+    "time"
+
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+    authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+
+    "github.com/your_repo/x/htlc"
+    "github.com/your_repo/x/htlc/testutil"
+)
+
+func (s *KeeperTestSuite) rescueFunds(authority sdk.AccAddress, amount int64) error {
+    return s.keeper.RescueFunds(s.ctx, htlc.MsgRescueFunds{
+        Authority: authority,
+        Recipient: s.other,
+        Amount:    sdk.NewCoins(sdk.NewInt64Coin("atom", amount)),
+    })
+}
+
+func (s *KeeperTestSuite) rescueHTLC(authority sdk.AccAddress, id string) error {
+    return s.keeper.RescueHTLC(s.ctx, htlc.MsgRescueHTLC{Authority: authority, ID: id, Recipient: s.other})
+}
+
+func (s *KeeperTestSuite) requireLocked(amount int64) {
+    locked, err := s.keeper.LockedBalance(s.ctx)
+    s.Require().NoError(err)
+    s.Require().True(sdk.NewCoins(sdk.NewInt64Coin("atom", amount)).IsEqual(locked), "locked %s", locked)
+}
+
+func (s *KeeperTestSuite) TestLockedBalance_FollowsHTLCs() {
+    secret := []byte("secret")
+    claimed := s.CreateHTLC(secret, 2*time.Hour)
+    refunded := s.CreateHTLC([]byte("other secret"), 2*time.Hour)
+    s.requireLocked(200)
+
+    s.AdvanceTime(time.Minute)
+    s.Require().NoError(s.claim(claimed.ID, s.receiver, secret, nil))
+    s.requireLocked(100)
+
+    s.SetBlockTime(refunded.TimeLock)
+    s.Require().NoError(s.refund(refunded.ID, s.sender))
+    s.requireLocked(0)
+}
+
+func (s *KeeperTestSuite) TestLockedBalance_PartialFills() {
+    tree := testutil.BuildMerkleTree(testutil.NewMerkleSecrets(4))
+    created := s.CreatePartialFill(tree, 2*time.Hour)
+    s.requireLocked(100)
+
+    s.AdvanceTime(time.Minute)
+    s.Require().NoError(s.claim(created.ID, s.receiver, tree.Secrets[0], tree.Proofs[0]))
+    s.requireLocked(75)
+
+    s.SetBlockTime(created.TimeLock)
+    s.Require().NoError(s.refund(created.ID, s.sender))
+    s.requireLocked(0)
+}
+
+func (s *KeeperTestSuite) TestRescueFunds_OnlySurplus() {
+    s.CreateHTLC([]byte("secret"), 2*time.Hour)
+    // Coins sent straight to the module account aren't owed to any HTLC
+    s.bank.FundAccount(authtypes.NewModuleAddress("htlc"), sdk.NewCoins(sdk.NewInt64Coin("atom", 50)))
+
+    s.Require().ErrorIs(s.rescueFunds(s.sender, 50), sdkerrors.ErrUnauthorized)
+    s.Require().ErrorIs(s.rescueFunds(s.authority, 51), sdkerrors.ErrInsufficientFunds)
+
+    s.Require().NoError(s.rescueFunds(s.authority, 50))
+    s.Require().Equal(sdk.NewCoins(sdk.NewInt64Coin("atom", 50)), s.bank.GetAllBalances(s.ctx, s.other))
+    s.Require().Equal(sdk.NewCoins(sdk.NewInt64Coin("atom", 100)), s.bank.ModuleBalance("htlc"))
+    s.Require().ErrorIs(s.rescueFunds(s.authority, 1), sdkerrors.ErrInsufficientFunds)
+}
+
+func (s *KeeperTestSuite) TestRescueHTLC_AfterDelay() {
+    created := s.CreateHTLC([]byte("secret"), 2*time.Hour)
+    rescuableAt := created.TimeLock.Add(htlc.RescueDelay)
+
+    s.SetBlockTime(rescuableAt.Add(-time.Second))
+    s.Require().ErrorIs(s.rescueHTLC(s.authority, created.ID), htlc.ErrRescueDelayNotElapsed)
+
+    s.SetBlockTime(rescuableAt)
+    s.Require().ErrorIs(s.rescueHTLC(s.sender, created.ID), sdkerrors.ErrUnauthorized)
+    s.Require().NoError(s.rescueHTLC(s.authority, created.ID))
+    s.Require().Equal(created.Amount, s.bank.GetAllBalances(s.ctx, s.other))
+    s.requireLocked(0)
+
+    rescued, err := s.keeper.GetHTLC(s.ctx, created.ID)
+    s.Require().NoError(err)
+    s.Require().True(rescued.Rescued)
+    s.Require().ErrorIs(s.rescueHTLC(s.authority, created.ID), htlc.ErrAlreadyRescued)
+}
+
+func (s *KeeperTestSuite) TestRescueHTLC_PartialFillRemainder() {
+    tree := testutil.BuildMerkleTree(testutil.NewMerkleSecrets(4))
+    created := s.CreatePartialFill(tree, 2*time.Hour)
+
+    s.AdvanceTime(time.Minute)
+    s.Require().NoError(s.claim(created.ID, s.receiver, tree.Secrets[0], tree.Proofs[0]))
+
+    s.SetBlockTime(created.TimeLock.Add(htlc.RescueDelay))
+    s.Require().NoError(s.rescueHTLC(s.authority, created.ID))
+    s.Require().Equal(sdk.NewCoins(sdk.NewInt64Coin("atom", 75)), s.bank.GetAllBalances(s.ctx, s.other))
+    s.Require().True(s.bank.ModuleBalance("htlc").IsZero())
+    s.requireLocked(0)
+}
@@ -0,0 +1,92 @@
+// x/htlc/route_test.go
+package htlc_test
+
+import (
+    "encoding/hex"
+    "time"
+
+    sdk "github.com/cosmos/cosmos-sdk/types"
+
+    "github.com/your_repo/x/htlc"
+)
+
+// routeMsg builds a three hop route ethereum -> local -> osmosis whose
+// timelocks are delta apart, the last one expiring after lock
+func (s *KeeperTestSuite) routeMsg(secret []byte, lock, delta time.Duration) htlc.MsgCreateRoute {
+    last := s.ctx.BlockTime().Add(lock)
+    return htlc.NewMsgCreateRoute(s.sender, sdk.Sha256(secret), []htlc.RouteHop{
+        {Chain: "ethereum", Sender: "0x01", Receiver: "0x02", Amount: "1000000000000000000", TimeLock: uint64(last.Add(2 * delta).Unix())},
+        {Receiver: s.receiver.String(), Amount: "100atom", TimeLock: uint64(last.Add(delta).Unix())},
+        {Chain: "ethereum", Sender: "0x03", Receiver: "0x04", Amount: "5", TimeLock: uint64(last.Unix())},
+    })
+}
+
+func (s *KeeperTestSuite) TestCreateRoute_CreatesLocalHop() {
+    secret := []byte("route secret")
+    msg := s.routeMsg(secret, 2*time.Hour, 3*time.Hour)
+    s.Require().NoError(msg.ValidateBasic())
+
+    id, err := s.keeper.CreateRoute(s.ctx, msg)
+    s.Require().NoError(err)
+
+    local, err := s.keeper.GetHTLC(s.ctx, id)
+    s.Require().NoError(err)
+    s.Require().Equal(s.receiver, local.Receiver)
+    s.Require().Equal(int64(msg.Hops[1].TimeLock), local.TimeLock.Unix())
+    s.Require().Equal(sdk.NewCoins(sdk.NewInt64Coin("atom", 100)), s.bank.ModuleBalance("htlc"))
+
+    var instructed []string
+    for _, event := range s.ctx.EventManager().Events() {
+        if event.Type != htlc.EventTypeRouteHop {
+            continue
+        }
+        attrs := map[string]string{}
+        for _, attr := range event.Attributes {
+            attrs[string(attr.Key)] = string(attr.Value)
+        }
+        s.Require().Equal(id, attrs[htlc.AttributeKeyRouteID])
+        s.Require().Equal(hex.EncodeToString(msg.HashLock), attrs[htlc.AttributeKeyHashLock])
+        instructed = append(instructed, attrs[htlc.AttributeKeyIndex])
+    }
+    s.Require().Equal([]string{"0", "2"}, instructed)
+}
+
+func (s *KeeperTestSuite) TestCreateRoute_RejectsUnsafeTimeLocks() {
+    secret := []byte("route secret")
+
+    _, err := s.keeper.CreateRoute(s.ctx, s.routeMsg(secret, 2*time.Hour, htlc.DefaultParams().MinRouteTimeLockDelta()-time.Second))
+    s.Require().ErrorContains(err, "at least 2h0m0s required")
+
+    // The last hop expires before the minimum timelock duration
+    _, err = s.keeper.CreateRoute(s.ctx, s.routeMsg(secret, time.Minute, 3*time.Hour))
+    s.Require().ErrorContains(err, "last hop timelock must be at least")
+    s.Require().True(s.bank.ModuleBalance("htlc").IsZero())
+}
+
+func (s *KeeperTestSuite) TestRouteStatus() {
+    secret := []byte("route secret")
+    msg := s.routeMsg(secret, 2*time.Hour, 3*time.Hour)
+    id, err := s.keeper.CreateRoute(s.ctx, msg)
+    s.Require().NoError(err)
+
+    status, err := s.keeper.RouteStatus(s.ctx, id)
+    s.Require().NoError(err)
+    s.Require().Equal(htlc.RouteStatusPending, status.Status)
+    s.Require().Equal(htlc.HopStatusPending, status.Hops[0].Status)
+    s.Require().Equal(htlc.StatusOpen.String(), status.Hops[1].Status)
+
+    // The final receiver claimed the last hop at its deadline, revealing the
+    // secret the local receiver now claims with
+    s.SetBlockTime(time.Unix(int64(msg.Hops[2].TimeLock), 0))
+    s.Require().NoError(s.claim(id, s.receiver, secret, nil))
+
+    status, err = s.keeper.RouteStatus(s.ctx, id)
+    s.Require().NoError(err)
+    s.Require().Equal(htlc.RouteStatusRevealed, status.Status)
+    s.Require().Equal(htlc.HopStatusClaimable, status.Hops[0].Status)
+    s.Require().Equal(htlc.StatusClaimed.String(), status.Hops[1].Status)
+    s.Require().Equal(htlc.HopStatusExpired, status.Hops[2].Status)
+
+    _, err = s.keeper.RouteStatus(s.ctx, "unknown")
+    s.Require().ErrorIs(err, htlc.ErrRouteNotFound)
+}
@@ -0,0 +1,183 @@
+// x/htlc/status.go
+package htlc
+
+import (
+    "fmt"
+    "time"
+
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// HTLCStatus is the state of an HTLC at a given block time
+type HTLCStatus int32
+
+const (
+    StatusOpen HTLCStatus = iota
+    StatusPartiallyFilled
+    StatusClaimed
+    StatusExpired
+    StatusRefunded
+    StatusPublicWithdrawable
+    StatusPublicCancellable
+    StatusRescued
+)
+
+var statusNames = map[HTLCStatus]string{
+    StatusOpen:               "open",
+    StatusPartiallyFilled:    "partially_filled",
+    StatusClaimed:            "claimed",
+    StatusExpired:            "expired",
+    StatusRefunded:           "refunded",
+    StatusPublicWithdrawable: "public_withdrawable",
+    StatusPublicCancellable:  "public_cancellable",
+    StatusRescued:            "rescued",
+}
+
+func (s HTLCStatus) String() string {
+    if name, ok := statusNames[s]; ok {
+        return name
+    }
+    return fmt.Sprintf("unknown(%d)", int32(s))
+}
+
+// HTLCAction is an operation that moves an HTLC out of its current status
+type HTLCAction int32
+
+const (
+    ActionClaim HTLCAction = iota
+    ActionRefund
+    ActionRescue
+)
+
+func (a HTLCAction) String() string {
+    switch a {
+    case ActionClaim:
+        return "claim"
+    case ActionRefund:
+        return "refund"
+    case ActionRescue:
+        return "rescue"
+    default:
+        return fmt.Sprintf("unknown(%d)", int32(a))
+    }
+}
+
+type actor int32
+
+const (
+    actorReceiver actor = iota
+    actorSender
+    actorAnyone
+    actorAuthority
+)
+
+// transitions lists, for every status, the actions allowed in it and who may
+// take them. Statuses missing from the table are terminal.
+var transitions = map[HTLCStatus]map[HTLCAction]actor{
+    StatusOpen: {
+        ActionClaim: actorReceiver,
+    },
+    StatusPartiallyFilled: {
+        ActionClaim: actorReceiver,
+    },
+    StatusPublicWithdrawable: {
+        ActionClaim: actorAnyone,
+    },
+    StatusExpired: {
+        ActionRefund: actorSender,
+        ActionRescue: actorAuthority,
+    },
+    StatusPublicCancellable: {
+        ActionRefund: actorAnyone,
+        ActionRescue: actorAuthority,
+    },
+}
+
+// ComputeStatus returns the status of htlc at block time now. The public
+// withdrawal and public cancellation stages around TimeLock, mirroring those
+// of the EVM escrows, are as long as params set them.
+func ComputeStatus(htlc HTLC, params Params, now time.Time) HTLCStatus {
+    switch {
+    case htlc.Claimed:
+        return StatusClaimed
+    case htlc.Refunded:
+        return StatusRefunded
+    case htlc.Rescued:
+        return StatusRescued
+    case !now.Before(htlc.TimeLock.Add(params.PublicCancellationDelay)):
+        return StatusPublicCancellable
+    case !now.Before(htlc.TimeLock):
+        return StatusExpired
+    case !now.Before(htlc.TimeLock.Add(-params.PublicWithdrawalPeriod)):
+        return StatusPublicWithdrawable
+    case htlc.FillCount > 0:
+        return StatusPartiallyFilled
+    default:
+        return StatusOpen
+    }
+}
+
+// ValidateTransition returns an error if action is not allowed in status
+func ValidateTransition(status HTLCStatus, action HTLCAction) error {
+    if _, ok := transitions[status][action]; ok {
+        return nil
+    }
+
+    switch status {
+    case StatusClaimed:
+        return ErrAlreadyClaimed
+    case StatusRefunded:
+        return ErrAlreadyRefunded
+    case StatusRescued:
+        return ErrAlreadyRescued
+    }
+    switch action {
+    case ActionClaim:
+        return ErrExpired
+    case ActionRefund, ActionRescue:
+        return ErrNotExpired
+    }
+    return sdkerrors.Wrapf(ErrInvalidTransition, "cannot %s HTLC in status %s", action, status)
+}
+
+// checkTransition validates that signer may take action on htlc at the current block time
+func (k Keeper) checkTransition(ctx sdk.Context, htlc HTLC, action HTLCAction, signer sdk.AccAddress) error {
+    status := ComputeStatus(htlc, k.GetParams(ctx), ctx.BlockTime())
+    if err := ValidateTransition(status, action); err != nil {
+        return err
+    }
+
+    switch transitions[status][action] {
+    case actorReceiver:
+        if !signer.Equals(htlc.Receiver) {
+            return ErrNotReceiver
+        }
+    case actorSender:
+        if !signer.Equals(htlc.Sender) {
+            return ErrNotSender
+        }
+    case actorAuthority:
+        if signer.String() != k.authority {
+            return sdkerrors.Wrapf(sdkerrors.ErrUnauthorized, "expected %s as authority, got %s", k.authority, signer)
+        }
+    }
+    return nil
+}
+
+// HTLCStatus returns the status of the HTLC with the given ID at the current block time
+func (k Keeper) HTLCStatus(ctx sdk.Context, id string) (HTLCStatus, error) {
+    htlc, err := k.GetHTLC(ctx, id)
+    if err != nil {
+        return 0, err
+    }
+    return ComputeStatus(htlc, k.GetParams(ctx), ctx.BlockTime()), nil
+}
+
+func (k Keeper) emitStatusChanged(ctx sdk.Context, htlc HTLC) {
+    ctx.EventManager().EmitEvent(sdk.NewEvent(
+        EventTypeStatusChanged,
+        sdk.NewAttribute(AttributeKeyID, htlc.ID),
+        sdk.NewAttribute(AttributeKeyStatus, ComputeStatus(htlc, k.GetParams(ctx), ctx.BlockTime()).String()),
+    ))
+}
@@ -0,0 +1,82 @@
+// x/htlc/status_test.go
+package htlc_test
+
+import (
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/require"
+
+    "github.com/your_repo/x/htlc"
+)
+
+func TestComputeStatus(t *testing.T) {
+    timeLock := time.Unix(1_700_000_000, 0)
+    open := htlc.HTLC{TimeLock: timeLock}
+
+    cases := []struct {
+        name   string
+        htlc   htlc.HTLC
+        now    time.Time
+        status htlc.HTLCStatus
+    }{
+        {"open", open, timeLock.Add(-htlc.DefaultPublicWithdrawalPeriod - time.Second), htlc.StatusOpen},
+        {"partially filled", htlc.HTLC{TimeLock: timeLock, FillCount: 1}, timeLock.Add(-2 * htlc.DefaultPublicWithdrawalPeriod), htlc.StatusPartiallyFilled},
+        {"public withdrawal starts", open, timeLock.Add(-htlc.DefaultPublicWithdrawalPeriod), htlc.StatusPublicWithdrawable},
+        {"one second before timelock", open, timeLock.Add(-time.Second), htlc.StatusPublicWithdrawable},
+        {"expired at timelock", open, timeLock, htlc.StatusExpired},
+        {"public cancellation starts", open, timeLock.Add(htlc.DefaultPublicCancellationDelay), htlc.StatusPublicCancellable},
+        {"claimed", htlc.HTLC{TimeLock: timeLock, Claimed: true}, timeLock.Add(time.Hour), htlc.StatusClaimed},
+        {"refunded", htlc.HTLC{TimeLock: timeLock, Refunded: true}, timeLock.Add(-time.Hour), htlc.StatusRefunded},
+        {"rescued", htlc.HTLC{TimeLock: timeLock, Rescued: true}, timeLock, htlc.StatusRescued},
+    }
+
+    for _, tc := range cases {
+        t.Run(tc.name, func(t *testing.T) {
+            require.Equal(t, tc.status, htlc.ComputeStatus(tc.htlc, htlc.DefaultParams(), tc.now))
+        })
+    }
+}
+
+func TestValidateTransition(t *testing.T) {
+    allowed := map[htlc.HTLCStatus][]htlc.HTLCAction{
+        htlc.StatusOpen:               {htlc.ActionClaim},
+        htlc.StatusPartiallyFilled:    {htlc.ActionClaim},
+        htlc.StatusPublicWithdrawable: {htlc.ActionClaim},
+        htlc.StatusExpired:            {htlc.ActionRefund, htlc.ActionRescue},
+        htlc.StatusPublicCancellable:  {htlc.ActionRefund, htlc.ActionRescue},
+    }
+    statuses := []htlc.HTLCStatus{
+        htlc.StatusOpen, htlc.StatusPartiallyFilled, htlc.StatusClaimed, htlc.StatusExpired,
+        htlc.StatusRefunded, htlc.StatusPublicWithdrawable, htlc.StatusPublicCancellable, htlc.StatusRescued,
+    }
+    actions := []htlc.HTLCAction{htlc.ActionClaim, htlc.ActionRefund, htlc.ActionRescue}
+
+    for _, status := range statuses {
+        for _, action := range actions {
+            t.Run(status.String()+"/"+action.String(), func(t *testing.T) {
+                err := htlc.ValidateTransition(status, action)
+                for _, a := range allowed[status] {
+                    if a == action {
+                        require.NoError(t, err)
+                        return
+                    }
+                }
+                require.Error(t, err)
+            })
+        }
+    }
+}
+
+func TestComputeStatus_ParamsStages(t *testing.T) {
+    timeLock := time.Unix(1_700_000_000, 0)
+    open := htlc.HTLC{TimeLock: timeLock}
+    params := htlc.DefaultParams()
+    params.PublicWithdrawalPeriod = 10 * time.Minute
+    params.PublicCancellationDelay = time.Hour
+
+    require.Equal(t, htlc.StatusOpen, htlc.ComputeStatus(open, params, timeLock.Add(-10*time.Minute-time.Second)))
+    require.Equal(t, htlc.StatusPublicWithdrawable, htlc.ComputeStatus(open, params, timeLock.Add(-10*time.Minute)))
+    require.Equal(t, htlc.StatusExpired, htlc.ComputeStatus(open, params, timeLock.Add(time.Hour-time.Second)))
+    require.Equal(t, htlc.StatusPublicCancellable, htlc.ComputeStatus(open, params, timeLock.Add(time.Hour)))
+}
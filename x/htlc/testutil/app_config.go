@@ -0,0 +1,64 @@
+// x/htlc/testutil/app_config.go
+package testutil
+
+import (
+    runtimev1alpha1 "cosmossdk.io/api/cosmos/app/runtime/v1alpha1"
+    appv1alpha1 "cosmossdk.io/api/cosmos/app/v1alpha1"
+    authmodulev1 "cosmossdk.io/api/cosmos/auth/module/v1"
+    bankmodulev1 "cosmossdk.io/api/cosmos/bank/module/v1"
+    consensusmodulev1 "cosmossdk.io/api/cosmos/consensus/module/v1"
+    feegrantmodulev1 "cosmossdk.io/api/cosmos/feegrant/module/v1"
+    genutilmodulev1 "cosmossdk.io/api/cosmos/genutil/module/v1"
+    stakingmodulev1 "cosmossdk.io/api/cosmos/staking/module/v1"
+    txconfigv1 "cosmossdk.io/api/cosmos/tx/config/v1"
+    "cosmossdk.io/core/appconfig"
+    "cosmossdk.io/depinject"
+    _ "cosmossdk.io/x/feegrant/module"
+
+    _ "github.com/cosmos/cosmos-sdk/x/auth"
+    _ "github.com/cosmos/cosmos-sdk/x/auth/tx/config"
+    authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+    _ "github.com/cosmos/cosmos-sdk/x/bank"
+    _ "github.com/cosmos/cosmos-sdk/x/consensus"
+    _ "github.com/cosmos/cosmos-sdk/x/genutil"
+    _ "github.com/cosmos/cosmos-sdk/x/staking"
+    stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+
+    htlcmodulev1 "github.com/your_repo/api/htlc/module/v1"
+    _ "github.com/your_repo/x/htlc"
+)
+
+// AppConfig is a minimal app wiring with the htlc module, used to spin up
+// in-process test networks
+var AppConfig = depinject.Configs(appconfig.Compose(&appv1alpha1.Config{
+    Modules: []*appv1alpha1.ModuleConfig{
+        {
+            Name: "runtime",
+            Config: appconfig.WrapAny(&runtimev1alpha1.Module{
+                AppName:       "HTLCApp",
+                BeginBlockers: []string{"staking"},
+                EndBlockers:   []string{"staking", "feegrant", "htlc"},
+                InitGenesis:   []string{"auth", "bank", "staking", "genutil", "feegrant", "consensus", "htlc"},
+            }),
+        },
+        {
+            Name: "auth",
+            Config: appconfig.WrapAny(&authmodulev1.Module{
+                Bech32Prefix: "cosmos",
+                ModuleAccountPermissions: []*authmodulev1.ModuleAccountPermission{
+                    {Account: authtypes.FeeCollectorName},
+                    {Account: stakingtypes.BondedPoolName, Permissions: []string{authtypes.Burner, stakingtypes.ModuleName}},
+                    {Account: stakingtypes.NotBondedPoolName, Permissions: []string{authtypes.Burner, stakingtypes.ModuleName}},
+                    {Account: "htlc"},
+                },
+            }),
+        },
+        {Name: "bank", Config: appconfig.WrapAny(&bankmodulev1.Module{})},
+        {Name: "staking", Config: appconfig.WrapAny(&stakingmodulev1.Module{})},
+        {Name: "tx", Config: appconfig.WrapAny(&txconfigv1.Config{})},
+        {Name: "genutil", Config: appconfig.WrapAny(&genutilmodulev1.Module{})},
+        {Name: "consensus", Config: appconfig.WrapAny(&consensusmodulev1.Module{})},
+        {Name: "feegrant", Config: appconfig.WrapAny(&feegrantmodulev1.Module{})},
+        {Name: "htlc", Config: appconfig.WrapAny(&htlcmodulev1.Module{})},
+    },
+}))
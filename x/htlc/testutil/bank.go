@@ -0,0 +1,53 @@
+// x/htlc/testutil/bank.go
+package testutil
+
+import (
+    "fmt"
+
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+    authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+)
+
+// MockBankKeeper is an in-memory bank keeper for keeper tests. Balances live
+// outside the multistore, so they are not reverted when a cached context is
+// discarded.
+type MockBankKeeper struct {
+    balances map[string]sdk.Coins
+}
+
+func NewMockBankKeeper() *MockBankKeeper {
+    return &MockBankKeeper{balances: make(map[string]sdk.Coins)}
+}
+
+// FundAccount mints coins straight into addr
+func (b *MockBankKeeper) FundAccount(addr sdk.AccAddress, coins sdk.Coins) {
+    b.balances[addr.String()] = b.balances[addr.String()].Add(coins...)
+}
+
+// ModuleBalance returns the coins held by the named module account
+func (b *MockBankKeeper) ModuleBalance(module string) sdk.Coins {
+    return b.balances[authtypes.NewModuleAddress(module).String()]
+}
+
+func (b *MockBankKeeper) GetAllBalances(_ sdk.Context, addr sdk.AccAddress) sdk.Coins {
+    return b.balances[addr.String()]
+}
+
+func (b *MockBankKeeper) SendCoinsFromAccountToModule(_ sdk.Context, senderAddr sdk.AccAddress, recipientModule string, amt sdk.Coins) error {
+    return b.send(senderAddr, authtypes.NewModuleAddress(recipientModule), amt)
+}
+
+func (b *MockBankKeeper) SendCoinsFromModuleToAccount(_ sdk.Context, senderModule string, recipientAddr sdk.AccAddress, amt sdk.Coins) error {
+    return b.send(authtypes.NewModuleAddress(senderModule), recipientAddr, amt)
+}
+
+func (b *MockBankKeeper) send(from, to sdk.AccAddress, amt sdk.Coins) error {
+    remaining, hasNeg := b.balances[from.String()].SafeSub(amt...)
+    if hasNeg {
+        return sdkerrors.Wrap(sdkerrors.ErrInsufficientFunds, fmt.Sprintf("%s < %s", b.balances[from.String()], amt))
+    }
+    b.balances[from.String()] = remaining
+    b.balances[to.String()] = b.balances[to.String()].Add(amt...)
+    return nil
+}
@@ -0,0 +1,274 @@
+// x/htlc/testutil/chain.go
+package testutil
+
+import (
+    "context"
+    "crypto/sha256"
+    "fmt"
+    "sync"
+    "testing"
+    "time"
+
+    "github.com/cosmos/gogoproto/proto"
+    "github.com/stretchr/testify/require"
+    "google.golang.org/grpc"
+    abci "github.com/tendermint/tendermint/abci/types"
+    "github.com/tendermint/tendermint/libs/log"
+    tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+    dbm "github.com/tendermint/tm-db"
+
+    "github.com/cosmos/cosmos-sdk/baseapp"
+    "github.com/cosmos/cosmos-sdk/codec"
+    codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+    "github.com/cosmos/cosmos-sdk/store"
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+    authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+
+    "github.com/your_repo/x/htlc"
+)
+
+// MockChain runs the htlc module in memory behind the same methods the Go
+// client uses to talk to a node. By default every broadcast tx is committed
+// right away in its own block; after DeferCommits txs wait in a mempool until
+// Commit. Signatures are not verified, sequences are.
+type MockChain struct {
+    Keeper *htlc.Keeper
+    Bank   *MockBankKeeper
+
+    mu           sync.Mutex
+    ctx          sdk.Context
+    cdc          codec.Codec
+    handler      sdk.Handler
+    queryRouter  *baseapp.GRPCQueryRouter
+    txDecoder    sdk.TxDecoder
+    accounts     map[string]*mockAccount
+    txs          map[string]*sdk.TxResponse
+    subs         []chan []abci.Event
+    deferCommits bool
+    mempool      []mempoolTx
+}
+
+const subscriptionBuffer = 64
+
+// mockAccount keeps the committed sequence, returned by Account, apart from
+// the one CheckTx expects, which also counts txs still in the mempool
+type mockAccount struct {
+    number        uint64
+    sequence      uint64
+    checkSequence uint64
+}
+
+type mempoolTx struct {
+    hash string
+    msgs []sdk.Msg
+    acc  *mockAccount
+}
+
+// NewMockChain returns a chain at height 1 whose block time starts at
+// genesis. txDecoder must know the htlc messages.
+func NewMockChain(tb testing.TB, txDecoder sdk.TxDecoder, genesis time.Time) *MockChain {
+    db := dbm.NewMemDB()
+    cms := store.NewCommitMultiStore(db)
+    key := sdk.NewKVStoreKey("htlc")
+    cms.MountStoreWithDB(key, sdk.StoreTypeIAVL, db)
+    require.NoError(tb, cms.LoadLatestVersion())
+
+    registry := codectypes.NewInterfaceRegistry()
+    htlc.RegisterInterfaces(registry)
+    cdc := codec.NewProtoCodec(registry)
+    bank := NewMockBankKeeper()
+    k := htlc.NewKeeper(cdc, key, bank, nil, "")
+
+    queryRouter := baseapp.NewGRPCQueryRouter()
+    queryRouter.SetInterfaceRegistry(registry)
+    htlc.RegisterQueryServer(queryRouter, htlc.NewQueryServerImpl(&k))
+
+    return &MockChain{
+        Keeper:      &k,
+        Bank:        bank,
+        ctx:         sdk.NewContext(cms, tmproto.Header{Height: 1, Time: genesis}, false, log.NewNopLogger()),
+        cdc:         cdc,
+        handler:     htlc.NewHandler(&k),
+        queryRouter: queryRouter,
+        txDecoder:   txDecoder,
+        accounts:    make(map[string]*mockAccount),
+        txs:         make(map[string]*sdk.TxResponse),
+    }
+}
+
+// DeferCommits makes BroadcastTx only check txs and queue them, like a node
+// answering in sync mode, until Commit puts them in a block
+func (c *MockChain) DeferCommits() {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    c.deferCommits = true
+}
+
+// PendingTxs returns the number of txs waiting for Commit
+func (c *MockChain) PendingTxs() int {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    return len(c.mempool)
+}
+
+// Commit delivers the queued txs in one new block
+func (c *MockChain) Commit() {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    c.commit(c.mempool)
+    c.mempool = nil
+}
+
+// AdvanceTime moves the block clock forward by d
+func (c *MockChain) AdvanceTime(d time.Duration) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    c.ctx = c.ctx.WithBlockTime(c.ctx.BlockTime().Add(d))
+}
+
+// Context returns the context of the current block
+func (c *MockChain) Context() sdk.Context {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    return c.ctx
+}
+
+// Invoke runs a query of the htlc Query service against the latest block, so
+// htlc.NewQueryClient(chain) works as it does over a node's gRPC connection
+func (c *MockChain) Invoke(_ context.Context, method string, args, reply interface{}, _ ...grpc.CallOption) error {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+
+    handler := c.queryRouter.Route(method)
+    if handler == nil {
+        return sdkerrors.Wrapf(sdkerrors.ErrUnknownRequest, "unknown query method %s", method)
+    }
+    req, err := c.cdc.Marshal(args.(proto.Message))
+    if err != nil {
+        return err
+    }
+    cacheCtx, _ := c.ctx.CacheContext()
+    res, err := handler(cacheCtx, &abci.RequestQuery{Path: method, Data: req})
+    if err != nil {
+        return err
+    }
+    return c.cdc.Unmarshal(res.Value, reply.(proto.Message))
+}
+
+func (c *MockChain) NewStream(context.Context, *grpc.StreamDesc, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+    return nil, fmt.Errorf("streaming queries are not supported")
+}
+
+func (c *MockChain) Account(_ context.Context, addr sdk.AccAddress) (uint64, uint64, error) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    acc := c.account(addr)
+    return acc.number, acc.sequence, nil
+}
+
+// BroadcastTx checks the sequence of the first signer against the txs it
+// accepted so far. It then commits the tx in a new block and returns the
+// committed response, or after DeferCommits queues it and returns the CheckTx
+// response.
+func (c *MockChain) BroadcastTx(_ context.Context, txBytes []byte) (*sdk.TxResponse, error) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+
+    hash := fmt.Sprintf("%X", sha256.Sum256(txBytes))
+    res := &sdk.TxResponse{TxHash: hash}
+
+    tx, err := c.txDecoder(txBytes)
+    if err != nil {
+        return fail(res, err), nil
+    }
+    sigTx, ok := tx.(authsigning.SigVerifiableTx)
+    if !ok {
+        return fail(res, sdkerrors.Wrap(sdkerrors.ErrTxDecode, "tx is not signed")), nil
+    }
+    sigs, err := sigTx.GetSignaturesV2()
+    if err != nil {
+        return fail(res, err), nil
+    }
+    msgs := tx.GetMsgs()
+    if len(msgs) == 0 || len(sigs) == 0 {
+        return fail(res, sdkerrors.Wrap(sdkerrors.ErrNoSignatures, "empty tx")), nil
+    }
+    signer, ok := msgs[0].(interface{ GetSigners() []sdk.AccAddress })
+    if !ok {
+        return fail(res, sdkerrors.Wrapf(sdkerrors.ErrUnknownRequest, "unexpected message %T", msgs[0])), nil
+    }
+    acc := c.account(signer.GetSigners()[0])
+    if sigs[0].Sequence != acc.checkSequence {
+        return fail(res, sdkerrors.Wrapf(sdkerrors.ErrWrongSequence, "expected %d, got %d", acc.checkSequence, sigs[0].Sequence)), nil
+    }
+    acc.checkSequence++
+
+    pending := mempoolTx{hash: hash, msgs: msgs, acc: acc}
+    if c.deferCommits {
+        c.mempool = append(c.mempool, pending)
+        return res, nil
+    }
+    c.commit([]mempoolTx{pending})
+    return c.txs[hash], nil
+}
+
+// commit delivers txs in a new block
+func (c *MockChain) commit(txs []mempoolTx) {
+    c.ctx = c.ctx.WithBlockHeight(c.ctx.BlockHeight() + 1)
+    for _, tx := range txs {
+        c.deliver(tx)
+    }
+    c.Keeper.PruneRevealedSecrets(c.ctx)
+}
+
+func (c *MockChain) deliver(tx mempoolTx) {
+    tx.acc.sequence++
+    res := &sdk.TxResponse{TxHash: tx.hash, Height: c.ctx.BlockHeight()}
+
+    cacheCtx, write := c.ctx.WithEventManager(sdk.NewEventManager()).CacheContext()
+    for _, msg := range tx.msgs {
+        if _, err := c.handler(cacheCtx, msg); err != nil {
+            c.txs[tx.hash] = fail(res, err)
+            return
+        }
+    }
+    write()
+
+    res.Events = cacheCtx.EventManager().ABCIEvents()
+    c.txs[tx.hash] = res
+    for _, sub := range c.subs {
+        sub <- res.Events
+    }
+}
+
+// Subscribe returns a channel receiving the events of every committed tx,
+// like a websocket subscription to a node. Broadcasting blocks once
+// subscriptionBuffer txs are left unread.
+func (c *MockChain) Subscribe() <-chan []abci.Event {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    sub := make(chan []abci.Event, subscriptionBuffer)
+    c.subs = append(c.subs, sub)
+    return sub
+}
+
+func (c *MockChain) GetTx(_ context.Context, hash string) (*sdk.TxResponse, error) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    return c.txs[hash], nil
+}
+
+func (c *MockChain) account(addr sdk.AccAddress) *mockAccount {
+    acc, ok := c.accounts[addr.String()]
+    if !ok {
+        acc = &mockAccount{number: uint64(len(c.accounts))}
+        c.accounts[addr.String()] = acc
+    }
+    return acc
+}
+
+func fail(res *sdk.TxResponse, err error) *sdk.TxResponse {
+    res.Codespace, res.Code, res.RawLog = sdkerrors.ABCIInfo(err, false)
+    return res
+}
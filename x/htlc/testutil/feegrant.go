@@ -0,0 +1,44 @@
+// x/htlc/testutil/feegrant.go
+package testutil
+
+import (
+    "context"
+
+    "cosmossdk.io/x/feegrant"
+
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// MockFeegrantKeeper is an in-memory feegrant keeper and Msg service for
+// keeper tests. Like x/feegrant it holds one allowance per granter/grantee.
+type MockFeegrantKeeper struct {
+    allowances map[string]feegrant.FeeAllowanceI
+}
+
+func NewMockFeegrantKeeper() *MockFeegrantKeeper {
+    return &MockFeegrantKeeper{allowances: make(map[string]feegrant.FeeAllowanceI)}
+}
+
+// Allowance returns the allowance granter gave grantee, nil if there is none
+func (f *MockFeegrantKeeper) Allowance(granter, grantee sdk.AccAddress) feegrant.FeeAllowanceI {
+    return f.allowances[granter.String()+"/"+grantee.String()]
+}
+
+func (f *MockFeegrantKeeper) GrantAllowance(_ context.Context, granter, grantee sdk.AccAddress, feeAllowance feegrant.FeeAllowanceI) error {
+    key := granter.String() + "/" + grantee.String()
+    if _, found := f.allowances[key]; found {
+        return sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "fee allowance already exists")
+    }
+    f.allowances[key] = feeAllowance
+    return nil
+}
+
+func (f *MockFeegrantKeeper) RevokeAllowance(_ context.Context, msg *feegrant.MsgRevokeAllowance) (*feegrant.MsgRevokeAllowanceResponse, error) {
+    key := msg.Granter + "/" + msg.Grantee
+    if _, found := f.allowances[key]; !found {
+        return nil, sdkerrors.Wrap(sdkerrors.ErrNotFound, "fee-grant not found")
+    }
+    delete(f.allowances, key)
+    return &feegrant.MsgRevokeAllowanceResponse{}, nil
+}
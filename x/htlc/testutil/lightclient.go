@@ -0,0 +1,144 @@
+// x/htlc/testutil/lightclient.go
+package testutil
+
+import (
+    "fmt"
+    "math/big"
+
+    "github.com/ethereum/go-ethereum/common"
+    "github.com/ethereum/go-ethereum/core/rawdb"
+    ethtypes "github.com/ethereum/go-ethereum/core/types"
+    "github.com/ethereum/go-ethereum/crypto"
+    "github.com/ethereum/go-ethereum/ethdb/memorydb"
+    "github.com/ethereum/go-ethereum/rlp"
+    "github.com/ethereum/go-ethereum/trie"
+    "github.com/ethereum/go-ethereum/triedb"
+
+    sdk "github.com/cosmos/cosmos-sdk/types"
+
+    "github.com/your_repo/x/htlc"
+)
+
+// MockLightClient is an in-memory light client keeper holding headers the
+// test declares verified
+type MockLightClient struct {
+    headers map[string]htlc.EthHeader
+}
+
+func NewMockLightClient() *MockLightClient {
+    return &MockLightClient{headers: make(map[string]htlc.EthHeader)}
+}
+
+// SetHeader marks header as the verified header of blockHash on chain
+func (c *MockLightClient) SetHeader(chain string, blockHash []byte, header htlc.EthHeader) {
+    c.headers[fmt.Sprintf("%s/%x", chain, blockHash)] = header
+}
+
+func (c *MockLightClient) GetEthHeader(_ sdk.Context, chain string, blockHash []byte) (htlc.EthHeader, bool) {
+    header, found := c.headers[fmt.Sprintf("%s/%x", chain, blockHash)]
+    return header, found
+}
+
+// EthTrie builds Merkle-Patricia tries and their proofs, as the receipt and
+// state tries of an Ethereum block
+type EthTrie struct {
+    trie *trie.Trie
+}
+
+func NewEthTrie() *EthTrie {
+    return &EthTrie{trie: trie.NewEmpty(triedb.NewDatabase(rawdb.NewMemoryDatabase(), nil))}
+}
+
+func (t *EthTrie) Put(key, value []byte) {
+    if err := t.trie.Update(key, value); err != nil {
+        panic(err)
+    }
+}
+
+func (t *EthTrie) Root() []byte {
+    return t.trie.Hash().Bytes()
+}
+
+// Prove returns the nodes on the path to key
+func (t *EthTrie) Prove(key []byte) [][]byte {
+    db := memorydb.New()
+    if err := t.trie.Prove(key, db); err != nil {
+        panic(err)
+    }
+    var proof [][]byte
+    it := db.NewIterator(nil, nil)
+    defer it.Release()
+    for it.Next() {
+        proof = append(proof, common.CopyBytes(it.Value()))
+    }
+    return proof
+}
+
+// ReceiptTrie returns the receipt trie of a block with the given receipts
+func ReceiptTrie(receipts ...*ethtypes.Receipt) *EthTrie {
+    t := NewEthTrie()
+    for i, receipt := range receipts {
+        value, err := receipt.MarshalBinary()
+        if err != nil {
+            panic(err)
+        }
+        t.Put(ReceiptKey(uint64(i)), value)
+    }
+    return t
+}
+
+// ReceiptKey is the receipt trie key of the txIndex-th transaction
+func ReceiptKey(txIndex uint64) []byte {
+    key, err := rlp.EncodeToBytes(txIndex)
+    if err != nil {
+        panic(err)
+    }
+    return key
+}
+
+// StateTrie returns a state trie holding contract accounts with the given
+// balances
+func StateTrie(balances map[common.Address]*big.Int) *EthTrie {
+    t := NewEthTrie()
+    for addr, balance := range balances {
+        value, err := rlp.EncodeToBytes([]interface{}{uint64(1), balance, ethtypes.EmptyRootHash, crypto.Keccak256([]byte("code"))})
+        if err != nil {
+            panic(err)
+        }
+        t.Put(AccountKey(addr), value)
+    }
+    return t
+}
+
+// AccountKey is the state trie key of addr
+func AccountKey(addr common.Address) []byte {
+    return crypto.Keccak256(addr.Bytes())
+}
+
+// DstEscrowCreatedLog is the log the factory emits when deploying escrow
+func DstEscrowCreatedLog(factory, escrow common.Address, hashLock []byte, taker common.Address) *ethtypes.Log {
+    data := append(common.LeftPadBytes(escrow.Bytes(), 32), hashLock...)
+    data = append(data, common.LeftPadBytes(taker.Bytes(), 32)...)
+    return &ethtypes.Log{Address: factory, Topics: []common.Hash{htlc.DstEscrowCreatedTopic}, Data: data}
+}
+
+// TransferLog is the ERC20 Transfer log of amount token from from to to
+func TransferLog(token, from, to common.Address, amount *big.Int) *ethtypes.Log {
+    return &ethtypes.Log{
+        Address: token,
+        Topics:  []common.Hash{htlc.TransferTopic, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())},
+        Data:    common.LeftPadBytes(amount.Bytes(), 32),
+    }
+}
+
+// SuccessfulReceipt returns a successful receipt holding logs
+func SuccessfulReceipt(logs ...*ethtypes.Log) *ethtypes.Receipt {
+    receipt := &ethtypes.Receipt{
+        Type:              ethtypes.DynamicFeeTxType,
+        Status:            ethtypes.ReceiptStatusSuccessful,
+        CumulativeGasUsed: 200_000,
+        Logs:              logs,
+    }
+    receipt.Bloom = ethtypes.CreateBloom(ethtypes.Receipts{receipt})
+    return receipt
+}
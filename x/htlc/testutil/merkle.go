@@ -0,0 +1,77 @@
+// x/htlc/testutil/merkle.go
+package testutil
+
+import (
+    "bytes"
+    "crypto/sha256"
+    "fmt"
+)
+
+// MerkleTree is a tree over hashed secrets, built with the same sorted-pair
+// hashing htlc.VerifyMerkleProof checks against
+type MerkleTree struct {
+    Secrets [][]byte
+    Root    []byte
+    Proofs  [][][]byte // Proofs[i] proves sha256(Secrets[i])
+}
+
+// NewMerkleSecrets returns n distinct deterministic 32-byte secrets
+func NewMerkleSecrets(n int) [][]byte {
+    secrets := make([][]byte, n)
+    for i := range secrets {
+        secret := sha256.Sum256([]byte(fmt.Sprintf("secret-%d", i)))
+        secrets[i] = secret[:]
+    }
+    return secrets
+}
+
+// BuildMerkleTree builds the tree over secrets. A node without a sibling is
+// promoted to the next level unchanged and adds nothing to the proofs.
+func BuildMerkleTree(secrets [][]byte) MerkleTree {
+    tree := MerkleTree{
+        Secrets: secrets,
+        Proofs:  make([][][]byte, len(secrets)),
+    }
+    if len(secrets) == 0 {
+        return tree
+    }
+
+    level := make([][]byte, len(secrets))
+    // positions[i] is the index at the current level of the node holding leaf i
+    positions := make([]int, len(secrets))
+    for i, secret := range secrets {
+        leaf := sha256.Sum256(secret)
+        level[i] = leaf[:]
+        positions[i] = i
+    }
+
+    for len(level) > 1 {
+        next := make([][]byte, 0, (len(level)+1)/2)
+        for i := 0; i < len(level); i += 2 {
+            if i+1 == len(level) {
+                next = append(next, level[i])
+                continue
+            }
+            next = append(next, hashPair(level[i], level[i+1]))
+        }
+        for leaf, pos := range positions {
+            if sibling := pos ^ 1; sibling < len(level) {
+                tree.Proofs[leaf] = append(tree.Proofs[leaf], level[sibling])
+            }
+            positions[leaf] = pos / 2
+        }
+        level = next
+    }
+    tree.Root = level[0]
+    return tree
+}
+
+func hashPair(a, b []byte) []byte {
+    var sum [32]byte
+    if bytes.Compare(a, b) < 0 {
+        sum = sha256.Sum256(append(append([]byte{}, a...), b...))
+    } else {
+        sum = sha256.Sum256(append(append([]byte{}, b...), a...))
+    }
+    return sum[:]
+}
@@ -2,25 +2,53 @@
 package htlc
 
 import (
-    sdk "github.com/cosmos/cosmos-sdk/types"
+    "fmt"
     "time"
+
+    sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
-type HTLC struct {
-    ID          string         // unique identifier
-    Sender      sdk.AccAddress // sender address
-    Receiver    sdk.AccAddress // receiver address
-    Amount      sdk.Coins      // locked tokens
-    HashLock    []byte         // hash of the secret
-    TimeLock    time.Time      // expiration time
-    Claimed     bool           // whether claimed
-    Refunded    bool           // whether refunded
+// HTLCID returns the ID of the HTLC created by sender at createdAt
+func HTLCID(sender sdk.AccAddress, createdAt time.Time) string {
+    return fmt.Sprintf("%s-%d", sender, createdAt.Unix())
+}
 
-    // New fields for cross-chain swaps
-    ExternalChain string // e.g., "ethereum"
-    ExternalID    string // ID of corresponding HTLC on external chain
+// IsOpen reports whether the HTLC still holds its funds
+func (h HTLC) IsOpen() bool {
+    return !h.Claimed && !h.Refunded && !h.Rescued
+}
+
+// Remaining returns the part of Amount the HTLC still holds, i.e. Amount
+// less what partial fills already released
+func (h HTLC) Remaining() sdk.Coins {
+    if len(h.MerkleRoot) == 0 {
+        return h.Amount
+    }
+    return h.Amount.Sub(filledAmount(h.Amount, h.Parts, h.FillCount)...)
+}
+
+// NextFill returns what the next claim releases, the whole Amount unless
+// the HTLC is a partial fill
+func (h HTLC) NextFill() sdk.Coins {
+    if len(h.MerkleRoot) == 0 {
+        return h.Amount
+    }
+    return filledAmount(h.Amount, h.Parts, h.FillCount+1).Sub(filledAmount(h.Amount, h.Parts, h.FillCount)...)
+}
 
-    // New fields for partial fills
-    MerkleRoot   []byte           // Merkle root of secrets for partial fills
-    UsedSecrets  map[string]bool  // Track used secrets (stringified)
+// filledAmount returns what the first fills of an amount split into parts
+// release. Each fill rounds down, so the last one also releases the remainder.
+func filledAmount(amount sdk.Coins, parts, fills uint64) sdk.Coins {
+    if parts == 0 {
+        parts = 1
+    }
+    if fills >= parts {
+        return amount
+    }
+    filled := sdk.NewCoins()
+    for _, coin := range amount {
+        share := coin.Amount.Mul(sdk.NewIntFromUint64(fills)).Quo(sdk.NewIntFromUint64(parts))
+        filled = filled.Add(sdk.NewCoin(coin.Denom, share))
+    }
+    return filled
 }